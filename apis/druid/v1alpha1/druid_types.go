@@ -8,6 +8,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -84,6 +85,14 @@ type DruidSpec struct {
 	// Required: in-container directory to mount with common.runtime.properties
 	CommonConfigMountPath string `json:"commonConfigMountPath"`
 
+	// Optional: files of ConfigMaps or Secrets mounted in the common config directory of all nodes,
+	// such as core-site.xml. Changes of their content restart the pods.
+	ConfigFrom []ConfigFromSource `json:"configFrom,omitempty"`
+
+	// Optional: extra files mounted in the common config directory of all nodes, by file name,
+	// such as core-site.xml or log4j2.xml.
+	ExtraCommonFiles map[string]string `json:"extraCommonFiles,omitempty"`
+
 	// Optional: Default is set to false, pvc shall be deleted on deletion of CR
 	DisablePVCDeletionFinalizer bool `json:"disablePVCDeletionFinalizer,omitempty"`
 
@@ -118,6 +127,15 @@ type DruidSpec struct {
 	// Optional: log4j config contents
 	Log4jConfig string `json:"log4j.config,omitempty"`
 
+	// Optional: generates log4j2.xml of the nodes, log4j.config taking precedence
+	Logging *LoggingSpec `json:"logging,omitempty"`
+
+	// Optional: extensions loaded by all nodes, extra extensions being copied to the pods by init containers
+	Extensions []ExtensionSpec `json:"extensions,omitempty"`
+
+	// Optional: image of the init containers downloading the extensions from a URL, defaults to curlimages/curl
+	ExtensionsInitImage string `json:"extensionsInitImage,omitempty"`
+
 	// Optional: druid pods pod-security-context
 	PodSecurityContext *v1.PodSecurityContext `json:"securityContext,omitempty"`
 
@@ -135,6 +153,10 @@ type DruidSpec struct {
 	// Optional: By default it is set to "parallel"
 	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
 
+	// Optional: changing this value restarts the pods of all druid nodes, honoring rollingDeploy ordering.
+	// Any string can be used, a timestamp such as 2006-01-02T15:04:05Z is recommended.
+	RestartedAt string `json:"restartedAt,omitempty"`
+
 	// Optional: custom labels to be populated in Druid pods
 	PodLabels map[string]string `json:"podLabels,omitempty"`
 
@@ -187,21 +209,105 @@ type DruidSpec struct {
 
 	// Optional: Custom Dimension Map Path for statsd emitter
 	DimensionsMapPath string `json:"metricDimensions.json,omitempty"`
+
+	// Optional: number of applied specs to keep as ControllerRevisions, defaults to 10.
+	// Annotate the CR with druid.apache.org/rollback-to: <revision> to re-apply one of them.
+	// +kubebuilder:validation:Minimum=1
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// Optional: coordinator dynamic config json, as accepted by /druid/coordinator/v1/config.
+	// Applied once the cluster is ready and re-applied on drift, fields not set are left untouched.
+	CoordinatorDynamicConfig string `json:"coordinatorDynamicConfig,omitempty"`
+
+	// Optional: overlord worker config json, as accepted by /druid/indexer/v1/worker.
+	// Applied once the cluster is ready and re-applied on drift, fields not set are left untouched.
+	OverlordDynamicConfig string `json:"overlordDynamicConfig,omitempty"`
+
+	// Optional: credentials used by the operator to call druid APIs, required with druid-basic-security
+	Auth *DruidAuthSpec `json:"auth,omitempty"`
+
+	// Optional: service discovery of druid nodes, defaults to zookeeper.
+	// zookeeperWithHTTP keeps zookeeper discovery with http based segment and task management, it is the intermediate
+	// step to migrate an existing cluster to kubernetes, which runs without zookeeper using druid-kubernetes-extensions.
+	// +kubebuilder:validation:Enum=zookeeper;zookeeperWithHTTP;kubernetes
+	Discovery string `json:"discovery,omitempty"`
+
+	// Optional: serves druid nodes over TLS only, on their druidPort, with a certificate issued per node spec
+	TLS *DruidTLSSpec `json:"tls,omitempty"`
+}
+
+// DruidTLSSpec defines how the certificates of druid nodes are issued.
+type DruidTLSSpec struct {
+	// Optional: BuiltIn issues the certificates from a CA generated by the operator and kept in a Secret,
+	// CertManager creates a cert-manager Certificate per node spec. Defaults to BuiltIn
+	// +kubebuilder:validation:Enum=BuiltIn;CertManager
+	Provider string `json:"provider,omitempty"`
+
+	// Optional: cert-manager issuer of the Certificates, required with the CertManager provider
+	IssuerRef *DruidTLSIssuerRef `json:"issuerRef,omitempty"`
+
+	// Optional: validity of the certificates, defaults to 2160h. BuiltIn certificates are renewed when a third of it remains
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Optional: image providing openssl 3.2 or later, used by the init container building the PKCS12 keystores,
+	// defaults to alpine/openssl
+	InitImage string `json:"initImage,omitempty"`
+
+	// Optional: enables the validation of the hostnames of the certificates by druid clients, requires druid.host to be
+	// one of the pod names the certificates are issued for, only StatefulSet pods having one
+	ValidateHostnames bool `json:"validateHostnames,omitempty"`
+}
+
+// DruidTLSIssuerRef references a cert-manager Issuer or ClusterIssuer.
+type DruidTLSIssuerRef struct {
+	// Required: name of the issuer
+	Name string `json:"name"`
+
+	// Optional: Issuer or ClusterIssuer, defaults to Issuer
+	Kind string `json:"kind,omitempty"`
+
+	// Optional: group of the issuer, defaults to cert-manager.io
+	Group string `json:"group,omitempty"`
+}
+
+// DruidAuthSpec holds the admin credentials and the basic security names used by the operator to call druid APIs.
+type DruidAuthSpec struct {
+	// Required: Secret holding the credentials of the admin user, in the namespace of the CR
+	SecretRef v1.LocalObjectReference `json:"secretRef"`
+
+	// Optional: key of the username in the Secret, defaults to username
+	UsernameKey string `json:"usernameKey,omitempty"`
+
+	// Optional: key of the password in the Secret, defaults to password
+	PasswordKey string `json:"passwordKey,omitempty"`
+
+	// Optional: name of the basic authenticator, defaults to MyBasicMetadataAuthenticator
+	AuthenticatorName string `json:"authenticatorName,omitempty"`
+
+	// Optional: name of the basic authorizer, defaults to MyBasicMetadataAuthorizer
+	AuthorizerName string `json:"authorizerName,omitempty"`
 }
 
 type DruidNodeSpec struct {
-	// Required: Druid node type e.g. Broker, Coordinator, Historical, MiddleManager, Router, Overlord etc
+	// Optional: key of another node spec of the CR this node spec inherits from, overriding only the fields set.
+	// Maps are merged, lists are replaced and runtime properties are merged by key.
+	Extends string `json:"extends,omitempty"`
+
+	// Required unless inherited with extends: Druid node type e.g. Broker, Coordinator, Historical, MiddleManager, Router, Overlord etc
+	// +optional
 	NodeType string `json:"nodeType"`
 
-	// Required: Port used by Druid Process
+	// Required unless inherited with extends: Port used by Druid Process
+	// +optional
 	DruidPort int32 `json:"druid.port"`
 
 	// Defaults to statefulsets.
 	// Note: volumeClaimTemplates are ignored when kind=Deployment
 	Kind string `json:"kind,omitempty"`
 
-	// Required
+	// Required unless inherited with extends
 	// +kubebuilder:validation:Minimum=0
+	// +optional
 	Replicas int32 `json:"replicas"`
 
 	// Optional
@@ -210,7 +316,8 @@ type DruidNodeSpec struct {
 	// Optional
 	PodDisruptionBudgetSpec *v1beta1.PodDisruptionBudgetSpec `json:"podDisruptionBudgetSpec,omitempty"`
 
-	// Required
+	// Required unless inherited with extends
+	// +optional
 	RuntimeProperties string `json:"runtime.properties"`
 
 	// Optional: This overrides JvmOptions at top level
@@ -222,7 +329,15 @@ type DruidNodeSpec struct {
 	// Optional: This overrides Log4jConfig at top level
 	Log4jConfig string `json:"log4j.config,omitempty"`
 
-	// Required: in-container directory to mount with runtime.properties, jvm.config, log4j2.xml files
+	// Optional: This overrides the fields of Logging at top level, and takes precedence over Log4jConfig at top level.
+	// Log4jConfig of the node takes precedence over it.
+	Logging *LoggingSpec `json:"logging,omitempty"`
+
+	// Optional: extensions loaded by the node in addition to the extensions at top level
+	Extensions []ExtensionSpec `json:"extensions,omitempty"`
+
+	// Required unless inherited with extends: in-container directory to mount with runtime.properties, jvm.config, log4j2.xml files
+	// +optional
 	NodeConfigMountPath string `json:"nodeConfigMountPath"`
 
 	// Optional: Overrides services at top level
@@ -273,12 +388,26 @@ type DruidNodeSpec struct {
 	// Optional: By default it is set to "parallel"
 	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
 
+	// Optional: changing this value restarts the pods of this node only, see restartedAt at cluster level.
+	RestartedAt string `json:"restartedAt,omitempty"`
+
 	// Optional: maxSurge for deployment object, only applicable if kind=Deployment
 	MaxSurge *int32 `json:"maxSurge,omitempty"`
 
 	// Optional: maxUnavailable for deployment object, only applicable if kind=Deployment
 	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
 
+	// Optional: blue/green rollout strategy, only applicable if kind=Deployment for broker and router nodes
+	BlueGreen *BlueGreenSpec `json:"blueGreen,omitempty"`
+
+	// Optional: only applicable to overlord nodes. kubernetes launches the peons as Kubernetes Jobs with
+	// druid-kubernetes-overlord-extensions, instead of on MiddleManagers. Defaults to remote
+	// +kubebuilder:validation:Enum=remote;kubernetes
+	TaskRunner string `json:"taskRunner,omitempty"`
+
+	// Optional: pod template of the peons launched by the kubernetes task runner
+	PeonTemplate *PeonTemplateSpec `json:"peonTemplate,omitempty"`
+
 	// Optional
 	UpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
 
@@ -306,12 +435,225 @@ type DruidNodeSpec struct {
 	// Optional
 	HPAutoScaler *autoscalev2beta2.HorizontalPodAutoscalerSpec `json:"hpAutoscaler,omitempty"`
 
+	// Optional: only applicable to middleManager and indexer nodes of kind StatefulSet. Scales the node spec on the
+	// pending and running tasks reported by the overlord, replicas is only used as the initial count.
+	TaskAutoscaler *TaskAutoscalerSpec `json:"taskAutoscaler,omitempty"`
+
 	// Optional
 	TopologySpreadConstraints []v1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 
 	VolumeClaimTemplates []v1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
 	VolumeMounts         []v1.VolumeMount           `json:"volumeMounts,omitempty"`
 	Volumes              []v1.Volume                `json:"volumes,omitempty"`
+
+	// Optional: only applicable to historical nodes of kind StatefulSet. Grows the volume claim template holding
+	// the segment cache when servers fill it, volume expansion happens as with scalePvcSts.
+	AutoExpand *VolumeAutoExpandSpec `json:"autoExpand,omitempty"`
+
+	// Optional: only applicable to historical nodes. Generates druid.segmentCache.locations and druid.server.maxSize
+	// from the sizes of the volume claim templates mounted in the node, overriding the runtime properties.
+	SegmentCache *SegmentCacheSpec `json:"segmentCache,omitempty"`
+
+	// Optional: auto sizes the heap, direct memory and processing properties from the cpu and memory limits
+	// of the resources. Options and properties set in the spec take precedence.
+	// +kubebuilder:validation:Enum=auto
+	MemoryProfile string `json:"memoryProfile,omitempty"`
+
+	// Optional: files of ConfigMaps or Secrets mounted in the config directory of the node,
+	// such as log4j2.xml. Changes of their content restart the pods.
+	ConfigFrom []ConfigFromSource `json:"configFrom,omitempty"`
+
+	// Optional: extra files mounted in the config directory of the node, by file name.
+	ExtraNodeFiles map[string]string `json:"extraNodeFiles,omitempty"`
+}
+
+// ConfigFromSource references a ConfigMap or a Secret in the namespace of the CR, its keys being mounted as files.
+// Files must not overwrite the ones generated by the operator.
+type ConfigFromSource struct {
+	// Optional: ConfigMap holding the files, exclusive with secretRef
+	ConfigMapRef *v1.LocalObjectReference `json:"configMapRef,omitempty"`
+
+	// Optional: Secret holding the files, exclusive with configMapRef
+	SecretRef *v1.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// Optional: keys mounted and their file names, all the keys are mounted by default
+	Items []v1.KeyToPath `json:"items,omitempty"`
+}
+
+// ExtensionSpec defines an extension added to druid.extensions.loadList. Core extensions only set the name, extra
+// extensions are copied from an image or downloaded from a URL into a shared volume by an init container.
+type ExtensionSpec struct {
+	// Required: name of the extension, and of its directory for extra extensions
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9._-]+$`
+	Name string `json:"name"`
+
+	// Optional: image holding the extension directory at path, exclusive with url
+	Image string `json:"image,omitempty"`
+
+	// Optional: directory of the extension jars in the image, required with image
+	Path string `json:"path,omitempty"`
+
+	// Optional: URL of a tar.gz archive of the extension jars, exclusive with image
+	URL string `json:"url,omitempty"`
+}
+
+// LoggingSpec defines the log4j2.xml generated by the operator. Logs are written to the console, and to rolling files
+// if rollingFile is set.
+type LoggingSpec struct {
+	// Optional: level of the root logger, defaults to info
+	// +kubebuilder:validation:Enum=trace;debug;info;warn;error;fatal;off
+	RootLevel string `json:"rootLevel,omitempty"`
+
+	// Optional: levels by logger name, such as org.apache.druid.server.coordinator: debug
+	Loggers map[string]string `json:"loggers,omitempty"`
+
+	// Optional: layout of the logs, defaults to pattern
+	// +kubebuilder:validation:Enum=pattern;json
+	Layout string `json:"layout,omitempty"`
+
+	// Optional: pattern of the pattern layout, defaults to %d{ISO8601} %p [%t] %c - %m%n
+	Pattern string `json:"pattern,omitempty"`
+
+	// Optional: writes the logs to rolling files in addition to the console
+	RollingFile *RollingFileSpec `json:"rollingFile,omitempty"`
+
+	// Optional: writes the request logs of druid.request.logging.type=slf4j to their own appender
+	RequestLog *LogAppenderSpec `json:"requestLog,omitempty"`
+
+	// Optional: writes the events of druid.emitter=logging to their own appender
+	Emitter *LogAppenderSpec `json:"emitter,omitempty"`
+}
+
+// RollingFileSpec defines the rolling files of the logs.
+type RollingFileSpec struct {
+	// Optional: directory of the log files, defaults to /druid/logs
+	Directory string `json:"directory,omitempty"`
+
+	// Optional: size rolling the file over, defaults to 100 MB
+	MaxFileSize string `json:"maxFileSize,omitempty"`
+
+	// Optional: number of rolled over files kept, defaults to 10
+	// +kubebuilder:validation:Minimum=1
+	MaxFiles int32 `json:"maxFiles,omitempty"`
+}
+
+// LogAppenderSpec defines an appender of a logger not propagating its logs to the root logger.
+type LogAppenderSpec struct {
+	// Optional: level of the logger, defaults to info
+	// +kubebuilder:validation:Enum=trace;debug;info;warn;error;fatal;off
+	Level string `json:"level,omitempty"`
+
+	// Optional: name of the file in the rolling file directory if rollingFile is set, logs are written to the
+	// console otherwise
+	FileName string `json:"fileName,omitempty"`
+}
+
+// SegmentCacheSpec defines the segment cache locations of historicals generated from their volumes.
+// A location is generated per volume with the size of the volume minus the headroom, maxSize is their sum.
+type SegmentCacheSpec struct {
+	// Optional: percentage of each volume left free for other files, defaults to 10
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=99
+	HeadroomPercent *int32 `json:"headroomPercent,omitempty"`
+
+	// Optional: names of the volume claim templates holding the segment cache, defaults to all the mounted ones
+	VolumeClaimTemplates []string `json:"volumeClaimTemplates,omitempty"`
+
+	// Optional: directory of the segment cache in each volume, defaults to segments
+	Directory string `json:"directory,omitempty"`
+}
+
+// VolumeAutoExpandSpec defines the growth of the segment cache volume of historicals.
+// Expanded sizes are kept in status, druid.server.maxSize and the segment cache locations on the volume are
+// scaled in proportion of the size in the spec.
+type VolumeAutoExpandSpec struct {
+	// Optional: percentage of the maxSize of a server used by its segments triggering an expansion, defaults to 80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	ThresholdPercent *int32 `json:"thresholdPercent,omitempty"`
+
+	// Size added to the volume claim template on each expansion
+	Step resource.Quantity `json:"step"`
+
+	// Maximum size of the volume claim template
+	MaxSize resource.Quantity `json:"maxSize"`
+
+	// Optional: name of the volume claim template holding the segment cache, defaults to the first one of the node spec
+	VolumeClaimTemplate string `json:"volumeClaimTemplate,omitempty"`
+}
+
+// BlueGreenSpec defines the blue/green rollout of a stateless node spec.
+// Operator stands up the inactive color Deployment with the new spec, waits for it to be fully deployed,
+// switches the service selectors to it and removes the previously active Deployment after a delay.
+type BlueGreenSpec struct {
+	// Optional: seconds to keep the previously active Deployment around after the switch, defaults to 300.
+	// Rollback is only possible while the previous Deployment exists.
+	// +kubebuilder:validation:Minimum=0
+	ScaleDownDelaySeconds *int32 `json:"scaleDownDelaySeconds,omitempty"`
+}
+
+// PeonTemplateSpec defines the pods of the peons launched by the kubernetes task runner.
+// Fields not set are taken from the cluster spec, as for node specs.
+type PeonTemplateSpec struct {
+	// Optional: runtime.properties of the peons, mounted next to the common ones
+	RuntimeProperties string `json:"runtime.properties,omitempty"`
+
+	// Optional: jvm options of the peons
+	JvmOptions string `json:"jvm.options,omitempty"`
+
+	// Optional: command of the peon container, defaults to sh -c "/peon.sh /druid/data 1"
+	Command []string `json:"command,omitempty"`
+
+	// Optional: Overrides image from top level
+	Image string `json:"image,omitempty"`
+
+	// Optional: CPU/Memory Resources
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Optional: Extra environment variables
+	Env []v1.EnvVar `json:"env,omitempty"`
+
+	// Optional: node selector of the peon pods
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Optional: tolerations of the peon pods
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: affinity of the peon pods
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+
+	// Optional: custom annotations of the peon pods
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
+	// Optional: custom labels of the peon pods
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+
+	VolumeMounts []v1.VolumeMount `json:"volumeMounts,omitempty"`
+	Volumes      []v1.Volume      `json:"volumes,omitempty"`
+}
+
+// TaskAutoscalerSpec defines the scaling of middleManager and indexer nodes on the task queue of the overlord.
+// Replicas are sized for the task slots of pending and running tasks, minus the slots of the workers of other
+// node specs. Workers are disabled before scale down, and removed once they have no running tasks.
+type TaskAutoscalerSpec struct {
+	// +kubebuilder:validation:Minimum=0
+	MinReplicas int32 `json:"minReplicas"`
+
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Optional: percentage of the task slots expected to be used, defaults to 100
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	TargetUtilizationPercent *int32 `json:"targetUtilizationPercent,omitempty"`
+
+	// Optional: seconds to wait after a scaling before scaling up, defaults to 60
+	// +kubebuilder:validation:Minimum=0
+	ScaleUpCooldownSeconds *int32 `json:"scaleUpCooldownSeconds,omitempty"`
+
+	// Optional: seconds to wait after a scaling before scaling down, defaults to 300
+	// +kubebuilder:validation:Minimum=0
+	ScaleDownCooldownSeconds *int32 `json:"scaleDownCooldownSeconds,omitempty"`
 }
 
 type ZookeeperSpec struct {
@@ -349,6 +691,42 @@ type DruidNodeTypeStatus struct {
 	Reason                   string                 `json:"reason,omitempty"`
 }
 
+// BlueGreenStatus records the blue/green rollout state of a node spec.
+type BlueGreenStatus struct {
+	// Color of the Deployment currently selected by the services
+	ActiveColor string `json:"activeColor,omitempty"`
+	// Last time the services were switched to another color
+	SwitchedAt *metav1.Time `json:"switchedAt,omitempty"`
+	// Hash of the Deployment that was rolled back, it shall not be rolled out again until the spec changes
+	RolledBackHash string `json:"rolledBackHash,omitempty"`
+}
+
+// TaskAutoscalerStatus defines the observed task queue and the last scaling decision of a node spec.
+type TaskAutoscalerStatus struct {
+	// Replicas decided by the autoscaler
+	Replicas int32 `json:"replicas"`
+	// Desired replicas computed on last poll of the overlord
+	DesiredReplicas int32 `json:"desiredReplicas"`
+	PendingTasks    int32 `json:"pendingTasks"`
+	RunningTasks    int32 `json:"runningTasks"`
+	// Task slots of all the enabled workers of the cluster
+	TotalCapacity int32 `json:"totalCapacity"`
+	// Last time replicas were changed
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+	// Pods disabled by the autoscaler, removed once their running tasks are complete
+	DisabledWorkers []string `json:"disabledWorkers"`
+	// Last decision of the autoscaler
+	Message string `json:"message"`
+}
+
+// VolumeAutoExpandStatus defines the size of the segment cache volume of a node spec grown by the operator.
+type VolumeAutoExpandStatus struct {
+	// Size of the volume claim template
+	Size resource.Quantity `json:"size"`
+	// Last time the volume was expanded
+	ExpandedAt *metav1.Time `json:"expandedAt,omitempty"`
+}
+
 // DruidStatus defines the observed state of Druid
 type DruidClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -363,6 +741,22 @@ type DruidClusterStatus struct {
 	HPAutoScalers          []string            `json:"hpAutoscalers,omitempty"`
 	Pods                   []string            `json:"pods,omitempty"`
 	PersistentVolumeClaims []string            `json:"persistentVolumeClaims,omitempty"`
+	// Blue/green rollout state keyed by node spec key
+	BlueGreen map[string]BlueGreenStatus `json:"blueGreen,omitempty"`
+	// Revision of the ControllerRevision holding the currently applied spec
+	CurrentRevision int64 `json:"currentRevision,omitempty"`
+	// Revision of the ControllerRevision holding the previously applied spec
+	PreviousRevision int64 `json:"previousRevision,omitempty"`
+	// Hash of the coordinator dynamic config last applied
+	CoordinatorDynamicConfigHash string `json:"coordinatorDynamicConfigHash,omitempty"`
+	// Hash of the overlord dynamic config last applied
+	OverlordDynamicConfigHash string `json:"overlordDynamicConfigHash,omitempty"`
+	// Task autoscaler state keyed by node spec key
+	TaskAutoscaler map[string]TaskAutoscalerStatus `json:"taskAutoscaler,omitempty"`
+	// Segment cache volume sizes grown by the operator keyed by node spec key
+	AutoExpand map[string]VolumeAutoExpandStatus `json:"autoExpand,omitempty"`
+	// Hash of the config lint warnings last reported as events
+	ConfigLintWarningsHash string `json:"configLintWarningsHash,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,78 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DruidCompactionConfig is the auto-compaction config of one datasource.
+type DruidCompactionConfig struct {
+	// Required: datasource to compact
+	DataSource string `json:"dataSource"`
+
+	// Optional: compaction config json, as accepted by /druid/coordinator/v1/config/compaction.
+	// dataSource is set by the operator, druid defaults are used if not set.
+	Config string `json:"config,omitempty"`
+}
+
+// DruidCompactionSpec defines the auto-compaction configs of a Druid cluster
+type DruidCompactionSpec struct {
+	// Required: name of the Druid CR in the same namespace
+	ClusterRef string `json:"clusterRef"`
+
+	// Required: compaction configs per datasource
+	Configs []DruidCompactionConfig `json:"configs"`
+
+	// Optional: deletes the compaction configs of datasources not declared by any DruidCompaction of the cluster
+	Prune bool `json:"prune,omitempty"`
+}
+
+// DruidCompactionDataSourceStatus is the compaction status of a datasource, as reported by the coordinator
+type DruidCompactionDataSourceStatus struct {
+	ScheduleStatus                 string `json:"scheduleStatus,omitempty"`
+	BytesAwaitingCompaction        int64  `json:"bytesAwaitingCompaction,omitempty"`
+	BytesCompacted                 int64  `json:"bytesCompacted,omitempty"`
+	BytesSkipped                   int64  `json:"bytesSkipped,omitempty"`
+	SegmentCountAwaitingCompaction int64  `json:"segmentCountAwaitingCompaction,omitempty"`
+	SegmentCountCompacted          int64  `json:"segmentCountCompacted,omitempty"`
+}
+
+// DruidCompactionStatus defines the observed state of DruidCompaction
+type DruidCompactionStatus struct {
+	// True if the coordinator compaction configs match the spec
+	Synced bool `json:"synced,omitempty"`
+	// Last time a compaction config was applied to the coordinator
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// Hash of the compaction config last applied to the coordinator keyed by datasource
+	SpecHashes map[string]string `json:"specHashes,omitempty"`
+	// Compaction status keyed by datasource
+	DataSources map[string]DruidCompactionDataSourceStatus `json:"dataSources,omitempty"`
+	// Human readable state of the sync with the coordinator
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Synced",type=boolean,JSONPath=`.status.synced`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// DruidCompaction is the Schema for the druidcompactions API
+type DruidCompaction struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DruidCompactionSpec   `json:"spec"`
+	Status DruidCompactionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DruidCompactionList contains a list of DruidCompaction
+type DruidCompactionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DruidCompaction `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DruidCompaction{}, &DruidCompactionList{})
+}
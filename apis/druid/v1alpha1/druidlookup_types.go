@@ -0,0 +1,68 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DruidLookupSpec defines a lookup of a Druid cluster tier
+type DruidLookupSpec struct {
+	// Required: name of the Druid CR in the same namespace
+	ClusterRef string `json:"clusterRef"`
+
+	// Optional: lookup tier, defaults to __default
+	Tier string `json:"tier,omitempty"`
+
+	// Optional: lookup name, defaults to the name of the DruidLookup
+	Name string `json:"name,omitempty"`
+
+	// Optional: lookup extractor factory json, exclusive with configMapRef
+	LookupExtractorFactory string `json:"lookupExtractorFactory,omitempty"`
+
+	// Optional: ConfigMap holding the data of a map lookup, each key of the ConfigMap data is a lookup key.
+	// Exclusive with lookupExtractorFactory
+	ConfigMapRef *v1.LocalObjectReference `json:"configMapRef,omitempty"`
+}
+
+// DruidLookupStatus defines the observed state of DruidLookup
+type DruidLookupStatus struct {
+	// Version of the lookup last submitted to the coordinator
+	Version string `json:"version,omitempty"`
+	// Hash of the lookup extractor factory last submitted to the coordinator
+	SpecHash string `json:"specHash,omitempty"`
+	// True once all the nodes of the tier have loaded the current version
+	Loaded bool `json:"loaded,omitempty"`
+	// Nodes of the tier which have not loaded the current version yet
+	PendingNodes []string `json:"pendingNodes,omitempty"`
+	// Human readable state of the sync with the coordinator
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Tier",type=string,JSONPath=`.spec.tier`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.version`
+// +kubebuilder:printcolumn:name="Loaded",type=boolean,JSONPath=`.status.loaded`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// DruidLookup is the Schema for the druidlookups API
+type DruidLookup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DruidLookupSpec   `json:"spec"`
+	Status DruidLookupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DruidLookupList contains a list of DruidLookup
+type DruidLookupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DruidLookup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DruidLookup{}, &DruidLookupList{})
+}
@@ -0,0 +1,115 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DruidOperationAction is the day-2 action run by a DruidOperation.
+// +kubebuilder:validation:Enum=RestartNode;ReloadSegments;KillTask;TriggerCompaction;ReplacePVC
+type DruidOperationAction string
+
+const (
+	// DruidOperationRestartNode restarts the pods of a node spec, honoring the rolling update of its workload.
+	DruidOperationRestartNode DruidOperationAction = "RestartNode"
+	// DruidOperationReloadSegments marks the segments of a datasource as used so that historicals load them again.
+	DruidOperationReloadSegments DruidOperationAction = "ReloadSegments"
+	// DruidOperationKillTask shuts down an ingestion task through the overlord.
+	DruidOperationKillTask DruidOperationAction = "KillTask"
+	// DruidOperationTriggerCompaction triggers the coordinator compaction duty.
+	DruidOperationTriggerCompaction DruidOperationAction = "TriggerCompaction"
+	// DruidOperationReplacePVC deletes the pod of a StatefulSet ordinal together with its PVCs,
+	// the StatefulSet recreates both fresh.
+	DruidOperationReplacePVC DruidOperationAction = "ReplacePVC"
+)
+
+// DruidOperationPhase is the phase of a DruidOperation.
+type DruidOperationPhase string
+
+const (
+	DruidOperationPending   DruidOperationPhase = "Pending"
+	DruidOperationRunning   DruidOperationPhase = "Running"
+	DruidOperationSucceeded DruidOperationPhase = "Succeeded"
+	DruidOperationFailed    DruidOperationPhase = "Failed"
+)
+
+// DruidOperationSpec defines the action to run against a Druid cluster
+type DruidOperationSpec struct {
+	// Required: name of the Druid CR in the same namespace
+	ClusterRef string `json:"clusterRef"`
+
+	// Required: action to run
+	Action DruidOperationAction `json:"action"`
+
+	// Optional: key of the node spec in Spec.Nodes, required by RestartNode and ReplacePVC
+	NodeSpecKey string `json:"nodeSpecKey,omitempty"`
+
+	// Optional: StatefulSet ordinal of the pod, required by ReplacePVC
+	// +kubebuilder:validation:Minimum=0
+	Ordinal *int32 `json:"ordinal,omitempty"`
+
+	// Optional: ReplacePVC decommissions the historical through the coordinator dynamic config
+	// and waits for its segments to be moved before deleting the PVCs. Skipped if the pod is not ready.
+	Decommission bool `json:"decommission,omitempty"`
+
+	// Optional: maximum wait of ReplacePVC for the segments to be moved off the decommissioned historical, defaults to 1h
+	DecommissionTimeout *metav1.Duration `json:"decommissionTimeout,omitempty"`
+
+	// Optional: maximum wait of ReplacePVC for the new pod to be ready and its segments to be loaded, defaults to 1h
+	LoadTimeout *metav1.Duration `json:"loadTimeout,omitempty"`
+
+	// Optional: datasource, required by ReloadSegments
+	DataSource string `json:"dataSource,omitempty"`
+
+	// Optional: ISO 8601 interval limiting the segments reloaded by ReloadSegments, all segments by default
+	Interval string `json:"interval,omitempty"`
+
+	// Optional: ingestion task id, required by KillTask
+	TaskID string `json:"taskId,omitempty"`
+}
+
+// DruidOperationStatus defines the observed state of DruidOperation
+type DruidOperationStatus struct {
+	Phase DruidOperationPhase `json:"phase,omitempty"`
+	// Time the operation started running
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Time the operation succeeded or failed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Human readable state of the operation
+	Message string `json:"message,omitempty"`
+	// Response of the Druid API, if any
+	Result string `json:"result,omitempty"`
+	// Current step of a multi step action
+	Step string `json:"step,omitempty"`
+	// Time the current step started
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+	// Druid server, as host:port, targeted by the action
+	Server string `json:"server,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.action`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// DruidOperation is the Schema for the druidoperations API
+type DruidOperation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DruidOperationSpec   `json:"spec"`
+	Status DruidOperationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DruidOperationList contains a list of DruidOperation
+type DruidOperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DruidOperation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DruidOperation{}, &DruidOperationList{})
+}
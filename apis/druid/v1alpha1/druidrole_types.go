@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DruidResource is a resource secured by druid-basic-security
+type DruidResource struct {
+	// Required: resource name, a regex for DATASOURCE resources
+	Name string `json:"name"`
+
+	// Required: resource type
+	// +kubebuilder:validation:Enum=DATASOURCE;CONFIG;STATE;SYSTEM_TABLE;EXTERNAL;VIEW
+	Type string `json:"type"`
+}
+
+// DruidPermission grants an action on a resource
+type DruidPermission struct {
+	Resource DruidResource `json:"resource"`
+
+	// Required: granted action
+	// +kubebuilder:validation:Enum=READ;WRITE
+	Action string `json:"action"`
+}
+
+// DruidRoleSpec defines a role of the druid-basic-security authorizer of a Druid cluster
+type DruidRoleSpec struct {
+	// Required: name of the Druid CR in the same namespace, the cluster must set spec.auth
+	ClusterRef string `json:"clusterRef"`
+
+	// Optional: role name, defaults to the name of the DruidRole
+	Name string `json:"name,omitempty"`
+
+	// Optional: permissions of the role, replacing the existing ones
+	Permissions []DruidPermission `json:"permissions,omitempty"`
+}
+
+// DruidRoleStatus defines the observed state of DruidRole
+type DruidRoleStatus struct {
+	// True if the druid role matches the spec
+	Synced bool `json:"synced,omitempty"`
+	// Hash of the permissions last set
+	PermissionsHash string `json:"permissionsHash,omitempty"`
+	// Human readable state of the sync with the coordinator
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Synced",type=boolean,JSONPath=`.status.synced`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// DruidRole is the Schema for the druidroles API
+type DruidRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DruidRoleSpec   `json:"spec"`
+	Status DruidRoleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DruidRoleList contains a list of DruidRole
+type DruidRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DruidRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DruidRole{}, &DruidRoleList{})
+}
@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DruidRetentionRule is a coordinator retention or load rule.
+type DruidRetentionRule struct {
+	// Required: rule type
+	// +kubebuilder:validation:Enum=loadForever;loadByPeriod;dropByPeriod;dropForever
+	Type string `json:"type"`
+
+	// Optional: ISO 8601 period, required by loadByPeriod and dropByPeriod
+	Period string `json:"period,omitempty"`
+
+	// Optional: whether the period includes the future, druid defaults to true
+	IncludeFuture *bool `json:"includeFuture,omitempty"`
+
+	// Optional: number of replicants per historical tier, used by load rules
+	TieredReplicants map[string]int32 `json:"tieredReplicants,omitempty"`
+}
+
+// DruidRuleSpec defines the rules of a datasource of a Druid cluster
+type DruidRuleSpec struct {
+	// Required: name of the Druid CR in the same namespace
+	ClusterRef string `json:"clusterRef"`
+
+	// Optional: datasource the rules apply to, cluster default rules if not set
+	DataSource string `json:"dataSource,omitempty"`
+
+	// Required: rules in the order they are evaluated by the coordinator
+	Rules []DruidRetentionRule `json:"rules"`
+}
+
+// DruidRuleStatus defines the observed state of DruidRule
+type DruidRuleStatus struct {
+	// True if the coordinator rules match the spec
+	Synced bool `json:"synced,omitempty"`
+	// Last time rules were applied to the coordinator
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// Last time the coordinator rules were found to differ from the previously applied ones
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// Human readable state of the rules
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="DataSource",type=string,JSONPath=`.spec.dataSource`
+// +kubebuilder:printcolumn:name="Synced",type=boolean,JSONPath=`.status.synced`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// DruidRule is the Schema for the druidrules API
+type DruidRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DruidRuleSpec   `json:"spec"`
+	Status DruidRuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DruidRuleList contains a list of DruidRule
+type DruidRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DruidRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DruidRule{}, &DruidRuleList{})
+}
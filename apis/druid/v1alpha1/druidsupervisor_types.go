@@ -0,0 +1,65 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DruidSupervisorSpec defines the streaming ingestion supervisor to run on a Druid cluster
+type DruidSupervisorSpec struct {
+	// Required: name of the Druid CR in the same namespace
+	ClusterRef string `json:"clusterRef"`
+
+	// Required: kafka or kinesis supervisor spec json, as submitted to /druid/indexer/v1/supervisor
+	SupervisorSpec string `json:"supervisorSpec"`
+
+	// Optional: suspends the supervisor, running tasks are stopped and no new task is started
+	Suspended bool `json:"suspended,omitempty"`
+}
+
+// DruidSupervisorStatus defines the observed state of DruidSupervisor
+type DruidSupervisorStatus struct {
+	// Id of the supervisor in druid
+	SupervisorID string `json:"supervisorId,omitempty"`
+	// Hash of the normalized supervisor spec last submitted to the overlord
+	SpecHash string `json:"specHash,omitempty"`
+	// State of the supervisor, as reported by the overlord
+	State string `json:"state,omitempty"`
+	// Detailed state of the supervisor, as reported by the overlord
+	DetailedState string `json:"detailedState,omitempty"`
+	Healthy       bool   `json:"healthy,omitempty"`
+	// Sum of the lag of all partitions, in offsets for kafka and in milliseconds for kinesis
+	AggregateLag int64 `json:"aggregateLag,omitempty"`
+	// Last time the supervisor spec was submitted to the overlord
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+	// Human readable state of the sync with the overlord
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Supervisor",type=string,JSONPath=`.status.supervisorId`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Lag",type=integer,JSONPath=`.status.aggregateLag`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// DruidSupervisor is the Schema for the druidsupervisors API
+type DruidSupervisor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DruidSupervisorSpec   `json:"spec"`
+	Status DruidSupervisorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DruidSupervisorList contains a list of DruidSupervisor
+type DruidSupervisorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DruidSupervisor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DruidSupervisor{}, &DruidSupervisorList{})
+}
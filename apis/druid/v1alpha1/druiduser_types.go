@@ -0,0 +1,61 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DruidUserSpec defines a user of the druid-basic-security authenticator and authorizer of a Druid cluster
+type DruidUserSpec struct {
+	// Required: name of the Druid CR in the same namespace, the cluster must set spec.auth
+	ClusterRef string `json:"clusterRef"`
+
+	// Optional: user name, defaults to the name of the DruidUser
+	Name string `json:"name,omitempty"`
+
+	// Optional: Secret key holding the password of the user, no credentials are set if not specified
+	PasswordSecretRef *v1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// Optional: names of the roles assigned to the user, roles not listed are unassigned
+	Roles []string `json:"roles,omitempty"`
+
+	// Optional: deletes the users not declared by any DruidUser of the cluster, except the admin and druid_system users
+	Prune bool `json:"prune,omitempty"`
+}
+
+// DruidUserStatus defines the observed state of DruidUser
+type DruidUserStatus struct {
+	// True if the druid user matches the spec
+	Synced bool `json:"synced,omitempty"`
+	// UID, resourceVersion and key of the password Secret last set
+	PasswordSecretVersion string `json:"passwordSecretVersion,omitempty"`
+	// Human readable state of the sync with the coordinator
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Synced",type=boolean,JSONPath=`.status.synced`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// DruidUser is the Schema for the druidusers API
+type DruidUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DruidUserSpec   `json:"spec"`
+	Status DruidUserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DruidUserList contains a list of DruidUser
+type DruidUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DruidUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DruidUser{}, &DruidUserList{})
+}
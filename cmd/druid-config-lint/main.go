@@ -0,0 +1,55 @@
+// druid-config-lint reports the issues found in the druid configuration of Druid CRs before they are applied,
+// as the operator does on reconcile. Exits with 1 if an error is found.
+//
+//	druid-config-lint [-warnings-as-errors] druid.yaml...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ghodss/yaml"
+
+	druidv1alpha1 "github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	"github.com/druid-io/druid-operator/controllers/druid"
+)
+
+func main() {
+	var warningsAsErrors bool
+	flag.BoolVar(&warningsAsErrors, "warnings-as-errors", false, "Exit with 1 if a warning is found.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] druid.yaml...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, file := range flag.Args() {
+		bytes, err := ioutil.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file, err.Error())
+			os.Exit(2)
+		}
+		m := &druidv1alpha1.Druid{}
+		if err := yaml.Unmarshal(bytes, m); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: invalid Druid CR due to [%s]\n", file, err.Error())
+			os.Exit(2)
+		}
+
+		for _, issue := range druid.LintDruidConfig(m) {
+			fmt.Printf("%s: %s: %s\n", file, issue.Severity, issue.String())
+			if issue.Severity == druid.ConfigLintError || warningsAsErrors {
+				failed = true
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
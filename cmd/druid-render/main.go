@@ -0,0 +1,57 @@
+// druid-render prints Druid CRs with the node specs extending other node specs resolved, as deployed by the operator.
+//
+//	druid-render druid.yaml...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ghodss/yaml"
+
+	druidv1alpha1 "github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	"github.com/druid-io/druid-operator/controllers/druid"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s druid.yaml...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	for i, file := range flag.Args() {
+		bytes, err := ioutil.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file, err.Error())
+			os.Exit(1)
+		}
+		m := &druidv1alpha1.Druid{}
+		if err := yaml.Unmarshal(bytes, m); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: invalid Druid CR due to [%s]\n", file, err.Error())
+			os.Exit(1)
+		}
+
+		nodes, err := druid.ResolveNodeSpecs(m)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file, err.Error())
+			os.Exit(1)
+		}
+		m.Spec.Nodes = nodes
+		rendered, err := yaml.Marshal(m)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file, err.Error())
+			os.Exit(1)
+		}
+		if i > 0 {
+			fmt.Println("---")
+		}
+		fmt.Print(string(rendered))
+	}
+}
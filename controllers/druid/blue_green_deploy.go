@@ -0,0 +1,270 @@
+package druid
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalev2beta2 "k8s.io/api/autoscaling/v2beta2"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	blueColor                             = "blue"
+	greenColor                            = "green"
+	blueGreenColorLabel                   = "druid_color"
+	blueGreenSpecHash                     = "druidBlueGreenSpecHash"
+	blueGreenRollbackAnnotation           = "druid.apache.org/bluegreen-rollback"
+	defaultBlueGreenScaleDownDelaySeconds = 300
+)
+
+func isBlueGreen(nodeSpec *v1alpha1.DruidNodeSpec) bool {
+	return nodeSpec.Kind == "Deployment" && nodeSpec.BlueGreen != nil
+}
+
+// getBlueGreenActiveColor returns the color currently selected by the services of the node spec, defaults to blue.
+func getBlueGreenActiveColor(m *v1alpha1.Druid, key string) string {
+	return firstNonEmptyStr(m.Status.BlueGreen[key].ActiveColor, blueColor)
+}
+
+// getBlueGreenSelectedColor returns the color selected by the services of the node spec, empty until the first
+// colored deployment is available, services then selecting the pods of the node spec whatever their color.
+func getBlueGreenSelectedColor(m *v1alpha1.Druid, key string) string {
+	return m.Status.BlueGreen[key].ActiveColor
+}
+
+func otherColor(color string) string {
+	if color == blueColor {
+		return greenColor
+	}
+	return blueColor
+}
+
+func makeBlueGreenDeploymentName(nodeSpecUniqueStr, color string) string {
+	return fmt.Sprintf("%s-%s", nodeSpecUniqueStr, color)
+}
+
+func makeBlueGreenLabels(ls map[string]string, color string) map[string]string {
+	labels := make(map[string]string, len(ls)+1)
+	for k, v := range ls {
+		labels[k] = v
+	}
+	labels[blueGreenColorLabel] = color
+	return labels
+}
+
+// makeBlueGreenDeployment shall create the deployment object for one color of a blue/green node spec.
+// Pods and selector of each color carry the color label so that both deployments can run side by side.
+func makeBlueGreenDeployment(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, ls map[string]string, nodeSpecUniqueStr, configMapSHA, serviceName, color string) (*appsv1.Deployment, error) {
+	deployment, err := makeDeployment(nodeSpec, m, makeBlueGreenLabels(ls, color), nodeSpecUniqueStr, configMapSHA, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	deployment.ObjectMeta.Name = makeBlueGreenDeploymentName(nodeSpecUniqueStr, color)
+
+	// hash is computed on the blue variant, so that both colors of the same spec share it.
+	if color != blueColor {
+		blue, err := makeBlueGreenDeployment(nodeSpec, m, ls, nodeSpecUniqueStr, configMapSHA, serviceName, blueColor)
+		if err != nil {
+			return nil, err
+		}
+		deployment.ObjectMeta.Annotations = map[string]string{blueGreenSpecHash: blue.Annotations[blueGreenSpecHash]}
+		return deployment, nil
+	}
+
+	sha, err := getObjectHash(deployment)
+	if err != nil {
+		return nil, err
+	}
+	deployment.ObjectMeta.Annotations = map[string]string{blueGreenSpecHash: sha}
+	return deployment, nil
+}
+
+// makeBlueGreenService shall create the service object selecting only the pods of the selected color, all the pods
+// of the node spec if no color is selected yet.
+func makeBlueGreenService(svc *v1.Service, nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, ls map[string]string, nodeSpecUniqueStr, color string) (*v1.Service, error) {
+	service, err := makeService(svc, nodeSpec, m, ls, nodeSpecUniqueStr)
+	if err != nil || color == "" {
+		return service, err
+	}
+	service.Spec.Selector = makeBlueGreenLabels(service.Spec.Selector, color)
+	return service, nil
+}
+
+// makeBlueGreenHorizontalPodAutoscaler shall create the HPA object scaling the deployment of the selected color,
+// the target of the spec if no color is selected yet.
+func makeBlueGreenHorizontalPodAutoscaler(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, ls map[string]string, nodeSpecUniqueStr, color string) (*autoscalev2beta2.HorizontalPodAutoscaler, error) {
+	hpa, err := makeHorizontalPodAutoscaler(nodeSpec, m, ls, nodeSpecUniqueStr)
+	if err != nil || color == "" {
+		return hpa, err
+	}
+	hpa.Spec.ScaleTargetRef = autoscalev2beta2.CrossVersionObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       makeBlueGreenDeploymentName(nodeSpecUniqueStr, color),
+	}
+	return hpa, nil
+}
+
+// deployBlueGreen rolls out a Deployment node spec with the blue/green strategy.
+// The new spec is rolled out to the inactive color, once it is fully deployed the active color is switched in the status
+// and services follow the status. The previously active color is kept for ScaleDownDelaySeconds to allow an instant rollback.
+// On the first rollout, the Deployment of the node spec created before blueGreen was enabled keeps serving until the
+// blue deployment is fully deployed, and is kept for ScaleDownDelaySeconds as well.
+// Returns true when the active color runs the desired spec.
+func deployBlueGreen(sdk client.Client, nodeSpec *v1alpha1.DruidNodeSpec, key, nodeSpecUniqueStr, configMapSHA, serviceName string, m *v1alpha1.Druid, ls map[string]string, names map[string]bool, emitEvents EventEmitter) (bool, error) {
+
+	active := getBlueGreenActiveColor(m, key)
+	inactive := otherColor(active)
+	activeName := makeBlueGreenDeploymentName(nodeSpecUniqueStr, active)
+	inactiveName := makeBlueGreenDeploymentName(nodeSpecUniqueStr, inactive)
+
+	desired, err := makeBlueGreenDeployment(nodeSpec, m, ls, nodeSpecUniqueStr, configMapSHA, serviceName, active)
+	if err != nil {
+		return false, err
+	}
+	desiredHash := desired.Annotations[blueGreenSpecHash]
+
+	if getBlueGreenSelectedColor(m, key) == "" {
+		names[nodeSpecUniqueStr] = true
+		if createUpdateStatus, err := sdkCreateOrUpdateAsNeeded(sdk,
+			func() (object, error) { return desired, nil },
+			func() object { return makeDeploymentEmptyObj() },
+			deploymentIsEquals, noopUpdaterFn, m, names, emitEvents); err != nil || createUpdateStatus != "" {
+			return false, err
+		}
+		done, err := isObjFullyDeployed(sdk, *nodeSpec, activeName, m, func() object { return makeDeploymentEmptyObj() }, emitEvents)
+		if !done {
+			return false, err
+		}
+		return false, switchBlueGreenColor(sdk, m, key, active, "", emitEvents)
+	}
+
+	activeObj := makeDeploymentEmptyObj()
+	if err := sdk.Get(context.TODO(), *namespacedName(activeName, m.Namespace), activeObj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		// active deployment deleted, nothing to switch from.
+		_, err := sdkCreateOrUpdateAsNeeded(sdk,
+			func() (object, error) { return desired, nil },
+			func() object { return makeDeploymentEmptyObj() },
+			deploymentIsEquals, noopUpdaterFn, m, names, emitEvents)
+		return err == nil, err
+	}
+	names[activeName] = true
+
+	inactiveObj := makeDeploymentEmptyObj()
+	inactiveExists := true
+	if err := sdk.Get(context.TODO(), *namespacedName(inactiveName, m.Namespace), inactiveObj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		inactiveExists = false
+	}
+
+	if ContainsString(strings.Split(m.GetAnnotations()[blueGreenRollbackAnnotation], ","), key) {
+		if err := removeBlueGreenRollbackRequest(sdk, m, key, emitEvents); err != nil {
+			return false, err
+		}
+
+		if !inactiveExists {
+			e := fmt.Errorf("Rollback of node [%s] requested but previous deployment [%s] does not exist anymore", key, inactiveName)
+			emitEvents.EmitEventGeneric(m, "DruidOperatorBlueGreenRollbackFail", "", e)
+			return false, nil
+		}
+
+		done, err := isObjFullyDeployed(sdk, *nodeSpec, inactiveName, m, func() object { return makeDeploymentEmptyObj() }, emitEvents)
+		if !done {
+			e := fmt.Errorf("Rollback of node [%s] requested but previous deployment [%s] is not fully deployed", key, inactiveName)
+			emitEvents.EmitEventGeneric(m, "DruidOperatorBlueGreenRollbackFail", "", e)
+			return false, err
+		}
+
+		names[inactiveName] = true
+		return false, switchBlueGreenColor(sdk, m, key, inactive, activeObj.Annotations[blueGreenSpecHash], emitEvents)
+	}
+
+	if activeObj.Annotations[blueGreenSpecHash] == desiredHash || m.Status.BlueGreen[key].RolledBackHash == desiredHash {
+		// Active color is up to date or the desired spec was rolled back, keep the previous color until the delay expires.
+		if !isBlueGreenScaleDownDue(nodeSpec, m.Status.BlueGreen[key]) {
+			names[nodeSpecUniqueStr] = true
+			if inactiveExists {
+				names[inactiveName] = true
+			}
+		}
+		return true, nil
+	}
+
+	// Roll out the desired spec to the inactive color
+	if createUpdateStatus, err := sdkCreateOrUpdateAsNeeded(sdk,
+		func() (object, error) {
+			return makeBlueGreenDeployment(nodeSpec, m, ls, nodeSpecUniqueStr, configMapSHA, serviceName, inactive)
+		},
+		func() object { return makeDeploymentEmptyObj() },
+		deploymentIsEquals, noopUpdaterFn, m, names, emitEvents); err != nil {
+		return false, err
+	} else if createUpdateStatus != "" {
+		// give deployment controller some time to update status of replicas
+		return false, nil
+	}
+
+	done, err := isObjFullyDeployed(sdk, *nodeSpec, inactiveName, m, func() object { return makeDeploymentEmptyObj() }, emitEvents)
+	if !done {
+		return false, err
+	}
+
+	return false, switchBlueGreenColor(sdk, m, key, inactive, "", emitEvents)
+}
+
+func isBlueGreenScaleDownDue(nodeSpec *v1alpha1.DruidNodeSpec, status v1alpha1.BlueGreenStatus) bool {
+	if status.SwitchedAt == nil {
+		return true
+	}
+	delay := int32(defaultBlueGreenScaleDownDelaySeconds)
+	if nodeSpec.BlueGreen.ScaleDownDelaySeconds != nil {
+		delay = *nodeSpec.BlueGreen.ScaleDownDelaySeconds
+	}
+	return time.Now().After(status.SwitchedAt.Add(time.Duration(delay) * time.Second))
+}
+
+// switchBlueGreenColor patches the active color of the node spec in the CR status, services are switched on next reconcile.
+func switchBlueGreenColor(sdk client.Client, m *v1alpha1.Druid, key, color, rolledBackHash string, emitEvents EventEmitter) error {
+	blueGreen := make(map[string]v1alpha1.BlueGreenStatus, len(m.Status.BlueGreen)+1)
+	for k, v := range m.Status.BlueGreen {
+		blueGreen[k] = v
+	}
+	now := metav1.Now()
+	blueGreen[key] = v1alpha1.BlueGreenStatus{
+		ActiveColor:    color,
+		SwitchedAt:     &now,
+		RolledBackHash: rolledBackHash,
+	}
+
+	if err := druidStatusFieldsPatcher(sdk, map[string]interface{}{"blueGreen": blueGreen}, m, emitEvents); err != nil {
+		return err
+	}
+	m.Status.BlueGreen = blueGreen
+
+	msg := fmt.Sprintf("Switched services of node [%s] to [%s] deployment", key, color)
+	logger.Info(msg, "name", m.Name, "namespace", m.Namespace)
+	emitEvents.EmitEventGeneric(m, "DruidOperatorBlueGreenSwitch", msg, nil)
+	return nil
+}
+
+func removeBlueGreenRollbackRequest(sdk client.Client, m *v1alpha1.Druid, key string, emitEvents EventEmitter) error {
+	patch := client.MergeFrom(m.DeepCopy())
+	keys := RemoveString(strings.Split(m.GetAnnotations()[blueGreenRollbackAnnotation], ","), key)
+	if len(keys) == 0 {
+		delete(m.Annotations, blueGreenRollbackAnnotation)
+	} else {
+		m.Annotations[blueGreenRollbackAnnotation] = strings.Join(keys, ",")
+	}
+	return writers.Patch(context.TODO(), sdk, m, m, false, patch, emitEvents)
+}
@@ -0,0 +1,101 @@
+package druid
+
+import (
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	autoscalev2beta2 "k8s.io/api/autoscaling/v2beta2"
+)
+
+func TestMakeBlueGreenDeploymentForBroker(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+
+	nodeSpecUniqueStr := makeNodeSpecificUniqueString(clusterSpec, "brokers")
+	nodeSpec := clusterSpec.Spec.Nodes["brokers"]
+	nodeSpec.Kind = "Deployment"
+	nodeSpec.BlueGreen = &v1alpha1.BlueGreenSpec{}
+	ls := makeLabelsForNodeSpec(&nodeSpec, clusterSpec, clusterSpec.Name, nodeSpecUniqueStr)
+
+	blue, err := makeBlueGreenDeployment(&nodeSpec, clusterSpec, ls, nodeSpecUniqueStr, "blah", nodeSpecUniqueStr, blueColor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	green, err := makeBlueGreenDeployment(&nodeSpec, clusterSpec, ls, nodeSpecUniqueStr, "blah", nodeSpecUniqueStr, greenColor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if blue.Name != nodeSpecUniqueStr+"-blue" || green.Name != nodeSpecUniqueStr+"-green" {
+		t.Errorf("unexpected deployment names [%s] [%s]", blue.Name, green.Name)
+	}
+
+	if blue.Annotations[blueGreenSpecHash] == "" || blue.Annotations[blueGreenSpecHash] != green.Annotations[blueGreenSpecHash] {
+		t.Errorf("both colors of the same spec must share the spec hash")
+	}
+
+	if blue.Spec.Selector.MatchLabels[blueGreenColorLabel] != blueColor || green.Spec.Template.Labels[blueGreenColorLabel] != greenColor {
+		t.Errorf("color label missing from selector or pod template")
+	}
+
+	if _, ok := ls[blueGreenColorLabel]; ok {
+		t.Errorf("node spec labels must not be mutated")
+	}
+
+	changed, _ := makeBlueGreenDeployment(&nodeSpec, clusterSpec, ls, nodeSpecUniqueStr, "changed", nodeSpecUniqueStr, greenColor)
+	if changed.Annotations[blueGreenSpecHash] == green.Annotations[blueGreenSpecHash] {
+		t.Errorf("spec hash must change with the config")
+	}
+}
+
+func TestMakeBlueGreenService(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+
+	nodeSpecUniqueStr := makeNodeSpecificUniqueString(clusterSpec, "brokers")
+	nodeSpec := clusterSpec.Spec.Nodes["brokers"]
+	ls := makeLabelsForNodeSpec(&nodeSpec, clusterSpec, clusterSpec.Name, nodeSpecUniqueStr)
+
+	actual, _ := makeBlueGreenService(&nodeSpec.Services[0], &nodeSpec, clusterSpec, ls, nodeSpecUniqueStr, greenColor)
+
+	if actual.Spec.Selector[blueGreenColorLabel] != greenColor {
+		t.Errorf("service must select the active color")
+	}
+	if _, ok := actual.Labels[blueGreenColorLabel]; ok {
+		t.Errorf("service labels must not carry the color")
+	}
+}
+
+func TestMakeBlueGreenServiceAndHPABeforeFirstSwitch(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+
+	nodeSpecUniqueStr := makeNodeSpecificUniqueString(clusterSpec, "brokers")
+	nodeSpec := clusterSpec.Spec.Nodes["brokers"]
+	nodeSpec.Kind = "Deployment"
+	nodeSpec.BlueGreen = &v1alpha1.BlueGreenSpec{}
+	nodeSpec.HPAutoScaler = &autoscalev2beta2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalev2beta2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: nodeSpecUniqueStr},
+		MaxReplicas:    4,
+	}
+	ls := makeLabelsForNodeSpec(&nodeSpec, clusterSpec, clusterSpec.Name, nodeSpecUniqueStr)
+
+	color := getBlueGreenSelectedColor(clusterSpec, "brokers")
+	if color != "" {
+		t.Fatalf("no color must be selected before the first switch, got [%s]", color)
+	}
+	service, _ := makeBlueGreenService(&nodeSpec.Services[0], &nodeSpec, clusterSpec, ls, nodeSpecUniqueStr, color)
+	if _, ok := service.Spec.Selector[blueGreenColorLabel]; ok {
+		t.Errorf("service must keep selecting the existing pods until the first switch")
+	}
+	hpa, _ := makeBlueGreenHorizontalPodAutoscaler(&nodeSpec, clusterSpec, ls, nodeSpecUniqueStr, color)
+	if hpa.Spec.ScaleTargetRef.Name != nodeSpecUniqueStr {
+		t.Errorf("HPA must keep its target until the first switch, got [%s]", hpa.Spec.ScaleTargetRef.Name)
+	}
+
+	clusterSpec.Status.BlueGreen = map[string]v1alpha1.BlueGreenStatus{"brokers": {ActiveColor: greenColor}}
+	hpa, _ = makeBlueGreenHorizontalPodAutoscaler(&nodeSpec, clusterSpec, ls, nodeSpecUniqueStr, getBlueGreenSelectedColor(clusterSpec, "brokers"))
+	if hpa.Spec.ScaleTargetRef.Name != makeBlueGreenDeploymentName(nodeSpecUniqueStr, greenColor) {
+		t.Errorf("HPA must scale the deployment of the active color, got [%s]", hpa.Spec.ScaleTargetRef.Name)
+	}
+	if nodeSpec.HPAutoScaler.ScaleTargetRef.Name != nodeSpecUniqueStr {
+		t.Errorf("HPA spec of the node spec must not be mutated")
+	}
+}
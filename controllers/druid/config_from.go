@@ -0,0 +1,152 @@
+package druid
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	configFromConfigMap = "ConfigMap"
+	configFromSecret    = "Secret"
+)
+
+// makeConfigVolumeSource returns the volume of a config directory holding the generated ConfigMap, projected
+// with the files of the sources if any.
+func makeConfigVolumeSource(configMapName string, sources []v1alpha1.ConfigFromSource) v1.VolumeSource {
+	generated := v1.LocalObjectReference{Name: configMapName}
+	if len(sources) == 0 {
+		return v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: generated}}
+	}
+
+	projections := []v1.VolumeProjection{{ConfigMap: &v1.ConfigMapProjection{LocalObjectReference: generated}}}
+	for _, source := range sources {
+		if source.ConfigMapRef != nil {
+			projections = append(projections, v1.VolumeProjection{
+				ConfigMap: &v1.ConfigMapProjection{LocalObjectReference: *source.ConfigMapRef, Items: source.Items},
+			})
+		} else if source.SecretRef != nil {
+			projections = append(projections, v1.VolumeProjection{
+				Secret: &v1.SecretProjection{LocalObjectReference: *source.SecretRef, Items: source.Items},
+			})
+		}
+	}
+	return v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{Sources: projections}}
+}
+
+// getConfigFromHash returns the hash of the content of the ConfigMaps and Secrets of the sources, empty without
+// sources, so that metadata only changes do not roll out the pods. Files of the sources must exist and not overwrite the files of the generated ConfigMap.
+func getConfigFromHash(sdk client.Client, m *v1alpha1.Druid, sources []v1alpha1.ConfigFromSource, generated *v1.ConfigMap, emitEvents EventEmitter) (string, error) {
+	if len(sources) == 0 {
+		return "", nil
+	}
+
+	// data is serialized with sorted keys
+	contents := make([]map[string][]byte, 0, len(sources))
+	for _, source := range sources {
+		name, data, err := getConfigFromData(sdk, m, source, emitEvents)
+		if err != nil {
+			return "", err
+		}
+
+		files := []string{}
+		if len(source.Items) == 0 {
+			for key := range data {
+				files = append(files, key)
+			}
+		}
+		for _, item := range source.Items {
+			if _, ok := data[item.Key]; !ok {
+				return "", fmt.Errorf("key [%s] not found in configFrom [%s]", item.Key, name)
+			}
+			files = append(files, item.Path)
+		}
+		for _, file := range files {
+			if _, ok := generated.Data[file]; ok {
+				return "", fmt.Errorf("file [%s] of configFrom [%s] overwrites a config file generated by the operator", file, name)
+			}
+		}
+		contents = append(contents, data)
+	}
+
+	return getJSONHash(contents)
+}
+
+// getConfigFromData returns the name and the content of the ConfigMap or Secret of the source.
+func getConfigFromData(sdk client.Client, m *v1alpha1.Druid, source v1alpha1.ConfigFromSource, emitEvents EventEmitter) (string, map[string][]byte, error) {
+	data := map[string][]byte{}
+	if source.ConfigMapRef != nil {
+		obj, err := readers.Get(context.TODO(), sdk, source.ConfigMapRef.Name, m, func() object { return makeConfigMapEmptyObj() }, emitEvents)
+		if err != nil {
+			return "", nil, err
+		}
+		configMap := obj.(*v1.ConfigMap)
+		for k, v := range configMap.Data {
+			data[k] = []byte(v)
+		}
+		for k, v := range configMap.BinaryData {
+			data[k] = v
+		}
+		return fmt.Sprintf("%s/%s", configFromConfigMap, configMap.Name), data, nil
+	}
+
+	obj, err := readers.Get(context.TODO(), sdk, source.SecretRef.Name, m, func() object { return &v1.Secret{} }, emitEvents)
+	if err != nil {
+		return "", nil, err
+	}
+	secret := obj.(*v1.Secret)
+	for k, v := range secret.Data {
+		data[k] = v
+	}
+	return fmt.Sprintf("%s/%s", configFromSecret, secret.Name), data, nil
+}
+
+// isValidConfigFrom returns true if each source references exactly one ConfigMap or Secret.
+func isValidConfigFrom(sources []v1alpha1.ConfigFromSource) bool {
+	for _, source := range sources {
+		if (source.ConfigMapRef == nil) == (source.SecretRef == nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// isConfigFromReferenced returns true if the ConfigMap or Secret is referenced by the configFrom of the cluster
+// or of a node spec.
+func isConfigFromReferenced(m *v1alpha1.Druid, kind, name string) bool {
+	sources := append([]v1alpha1.ConfigFromSource{}, m.Spec.ConfigFrom...)
+	for _, nodeSpec := range getNodeSpecs(m) {
+		sources = append(sources, nodeSpec.ConfigFrom...)
+	}
+	for _, source := range sources {
+		if (kind == configFromConfigMap && source.ConfigMapRef != nil && source.ConfigMapRef.Name == name) ||
+			(kind == configFromSecret && source.SecretRef != nil && source.SecretRef.Name == name) {
+			return true
+		}
+	}
+	return false
+}
+
+// makeConfigFromRequestsFn returns the function mapping a ConfigMap or Secret to the Druid CRs of its namespace
+// referencing it, so that changes of its content roll the pods out.
+func makeConfigFromRequestsFn(sdk client.Client, kind string) func(client.Object) []reconcile.Request {
+	return func(obj client.Object) []reconcile.Request {
+		list := &v1alpha1.DruidList{}
+		if err := sdk.List(context.TODO(), list, client.InNamespace(obj.GetNamespace())); err != nil {
+			logger.Error(err, "failed to list Druid CRs", "namespace", obj.GetNamespace())
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for i := range list.Items {
+			if isConfigFromReferenced(&list.Items[i], kind, obj.GetName()) {
+				requests = append(requests, reconcile.Request{NamespacedName: *namespacedName(list.Items[i].Name, list.Items[i].Namespace)})
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,56 @@
+package druid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestMakeConfigVolumeSource(t *testing.T) {
+	if source := makeConfigVolumeSource("druid-test-config", nil); source.ConfigMap == nil || source.ConfigMap.Name != "druid-test-config" {
+		t.Errorf("volume without configFrom must be the generated ConfigMap, got %+v", source)
+	}
+
+	source := makeConfigVolumeSource("druid-test-config", []v1alpha1.ConfigFromSource{
+		{ConfigMapRef: &v1.LocalObjectReference{Name: "hadoop"}},
+		{SecretRef: &v1.LocalObjectReference{Name: "keystore"}, Items: []v1.KeyToPath{{Key: "jks", Path: "keystore.jks"}}},
+	})
+	if source.Projected == nil || len(source.Projected.Sources) != 3 {
+		t.Fatalf("volume with configFrom must be projected, got %+v", source)
+	}
+	projections := source.Projected.Sources
+	if projections[0].ConfigMap.Name != "druid-test-config" || projections[1].ConfigMap.Name != "hadoop" ||
+		projections[2].Secret.Name != "keystore" || projections[2].Secret.Items[0].Path != "keystore.jks" {
+		t.Errorf("unexpected projections %+v", projections)
+	}
+}
+
+func TestConfigFromReferences(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	clusterSpec.Spec.ConfigFrom = []v1alpha1.ConfigFromSource{{ConfigMapRef: &v1.LocalObjectReference{Name: "hadoop"}}}
+	brokers := clusterSpec.Spec.Nodes["brokers"]
+	brokers.ConfigFrom = []v1alpha1.ConfigFromSource{{SecretRef: &v1.LocalObjectReference{Name: "keystore"}}}
+	clusterSpec.Spec.Nodes["brokers"] = brokers
+
+	for _, tc := range []struct {
+		kind, name string
+		expected   bool
+	}{
+		{configFromConfigMap, "hadoop", true},
+		{configFromSecret, "keystore", true},
+		{configFromSecret, "hadoop", false},
+		{configFromConfigMap, "druid-test-druid-common-config", false},
+	} {
+		if isConfigFromReferenced(clusterSpec, tc.kind, tc.name) != tc.expected {
+			t.Errorf("%s/%s referenced must be %t", tc.kind, tc.name, tc.expected)
+		}
+	}
+
+	brokers.ConfigFrom = append(brokers.ConfigFrom, v1alpha1.ConfigFromSource{})
+	clusterSpec.Spec.Nodes["brokers"] = brokers
+	if _, err := verifyDruidSpec(clusterSpec); err == nil || !strings.Contains(err.Error(), "Node[brokers] configFrom sources must reference exactly one") {
+		t.Errorf("configFrom without reference must be rejected, got [%v]", err)
+	}
+}
@@ -0,0 +1,278 @@
+package druid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ConfigLintError is the severity of issues blocking the rollout of the cluster
+	ConfigLintError = "error"
+	// ConfigLintWarning is the severity of issues only reported as events
+	ConfigLintWarning = "warning"
+
+	coordinatorAsOverlordProperty = "druid.coordinator.asOverlord.enabled"
+	deepStorageTypeProperty       = "druid.storage.type"
+	metadataStorageTypeProperty   = "druid.metadata.storage.type"
+	indexerLogsTypeProperty       = "druid.indexer.logs.type"
+)
+
+// ConfigLintIssue is a problem found in the druid configuration of a node spec.
+type ConfigLintIssue struct {
+	Severity string
+	NodeSpec string
+	Message  string
+}
+
+func (i ConfigLintIssue) String() string {
+	if i.NodeSpec == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("Node[%s] %s", i.NodeSpec, i.Message)
+}
+
+// extensions providing the storage types set in the common or node properties
+var storageTypeExtensions = map[string]map[string]string{
+	deepStorageTypeProperty: {
+		"s3": "druid-s3-extensions", "google": "druid-google-extensions", "azure": "druid-azure-extensions",
+		"hdfs": "druid-hdfs-storage", "cassandra": "druid-cassandra-storage", "oss": "aliyun-oss-extensions",
+	},
+	indexerLogsTypeProperty: {
+		"s3": "druid-s3-extensions", "google": "druid-google-extensions", "azure": "druid-azure-extensions",
+		"hdfs": "druid-hdfs-storage", "oss": "aliyun-oss-extensions",
+	},
+	metadataStorageTypeProperty: {
+		"mysql": "mysql-metadata-storage", "postgresql": "postgresql-metadata-storage", "sqlserver": "sqlserver-metadata-storage",
+	},
+}
+
+// node types reading the node properties starting with the prefixes, properties are inherited by the peons
+// of middleManagers
+var nodeTypeProperties = []struct {
+	prefix    string
+	nodeTypes []string
+}{
+	{"druid.processing.", []string{historical, broker, indexer, middleManager}},
+	{"druid.segmentCache.", []string{historical, indexer, middleManager}},
+	{"druid.server.maxSize", []string{historical, indexer, middleManager}},
+	{"druid.server.tier", []string{historical, indexer, middleManager}},
+	{"druid.server.priority", []string{historical, indexer, middleManager}},
+	{"druid.historical.", []string{historical}},
+	{"druid.broker.", []string{broker}},
+	{"druid.router.", []string{router}},
+	{"druid.coordinator.", []string{coordinator}},
+	{"druid.indexer.queue.", []string{overlord}},
+	{"druid.indexer.storage.", []string{overlord}},
+	{"druid.indexer.runner.", []string{overlord, middleManager, indexer}},
+	{"druid.indexer.task.", []string{overlord, middleManager, indexer}},
+	{"druid.indexer.fork.property.", []string{middleManager}},
+	{"druid.worker.", []string{middleManager, indexer}},
+}
+
+// LintDruidConfig returns the issues found in the common and node runtime properties and jvm options of the cluster,
+// including the ones generated by the operator. Templates are rendered first, failing ones being reported as errors.
+func LintDruidConfig(m *v1alpha1.Druid) []ConfigLintIssue {
+	commonProperties, err := renderCommonRuntimeProperties(m)
+	if err != nil {
+		return []ConfigLintIssue{{Severity: ConfigLintError, Message: err.Error()}}
+	}
+
+	nodes := getNodeSpecs(m)
+	keys := make([]string, 0, len(nodes))
+	for key := range nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	issues := []ConfigLintIssue{}
+	for _, key := range keys {
+		nodeSpec := nodes[key]
+		rendered, err := renderNodeSpecTemplates(&nodeSpec, m, makeNodeSpecificUniqueString(m, key))
+		if err != nil {
+			issues = append(issues, ConfigLintIssue{Severity: ConfigLintError, NodeSpec: key, Message: err.Error()})
+			continue
+		}
+		for _, issue := range lintNodeConfig(rendered, commonProperties, m) {
+			issue.NodeSpec = key
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// emitConfigLintWarnings reports the lint warnings of the cluster as events when they changed since last reported.
+// The hash of the reported warnings is recorded in status.
+func emitConfigLintWarnings(sdk client.Client, m *v1alpha1.Druid, issues []ConfigLintIssue, emitEvents EventEmitter) error {
+	warnings := []string{}
+	for _, issue := range issues {
+		if issue.Severity == ConfigLintWarning {
+			warnings = append(warnings, issue.String())
+		}
+	}
+
+	hash := ""
+	if len(warnings) > 0 {
+		var err error
+		if hash, err = getJSONHash(warnings); err != nil {
+			return err
+		}
+	}
+	if hash == m.Status.ConfigLintWarningsHash {
+		return nil
+	}
+
+	for _, warning := range warnings {
+		emitEvents.EmitEventGeneric(m, "DruidConfigLintWarning", "", errors.New(warning))
+	}
+	return druidStatusFieldsPatcher(sdk, map[string]interface{}{"configLintWarningsHash": hash}, m, emitEvents)
+}
+
+// lintNodeConfig returns the issues of the node spec, its templates and the common properties being rendered.
+func lintNodeConfig(nodeSpec *v1alpha1.DruidNodeSpec, commonProperties string, m *v1alpha1.Druid) []ConfigLintIssue {
+	issues := []ConfigLintIssue{}
+	addIssue := func(severity, format string, a ...interface{}) {
+		issues = append(issues, ConfigLintIssue{Severity: severity, Message: fmt.Sprintf(format, a...)})
+	}
+
+	// memory profile without memory limit is rejected by verifyDruidSpec
+	memoryProfileJvm, memoryProfileProp, _ := makeMemoryProfile(nodeSpec, m)
+	// invalid load list is reported below
+	extensionsProp, _ := makeExtensionsRuntimeProperties(nodeSpec, m)
+	// node properties override the common ones
+	properties := fmt.Sprintf("%s\n%s%s\n%s", commonProperties, memoryProfileProp, nodeSpec.RuntimeProperties, extensionsProp)
+	jvmOptions := fmt.Sprintf("%s%s\n%s", memoryProfileJvm, nodeSpec.JvmOptions, nodeSpec.ExtraJvmOptions)
+
+	// extensions
+	if value, found := getPropertyValue(properties, extensionsLoadListProperty); found {
+		loadList := []string{}
+		if err := json.Unmarshal([]byte(value), &loadList); err != nil {
+			addIssue(ConfigLintError, "invalid %s [%s] due to [%s]", extensionsLoadListProperty, value, err.Error())
+		} else {
+			for _, property := range []string{deepStorageTypeProperty, indexerLogsTypeProperty, metadataStorageTypeProperty} {
+				storageType, _ := getPropertyValue(properties, property)
+				if ext, ok := storageTypeExtensions[property][storageType]; ok && !ContainsString(loadList, ext) {
+					addIssue(ConfigLintError, "%s [%s] requires extension [%s] missing from %s", property, storageType, ext, extensionsLoadListProperty)
+				}
+			}
+		}
+	}
+
+	// properties of other node types
+	nodeTypes := []string{nodeSpec.NodeType}
+	if value, _ := getPropertyValue(properties, coordinatorAsOverlordProperty); nodeSpec.NodeType == coordinator && value == "true" {
+		nodeTypes = append(nodeTypes, overlord)
+	}
+	for _, line := range strings.Split(nodeSpec.RuntimeProperties, "\n") {
+		property, ok := getPropertyKey(line)
+		if !ok {
+			continue
+		}
+		for _, p := range nodeTypeProperties {
+			if !strings.HasPrefix(property, p.prefix) {
+				continue
+			}
+			if !containsAnyString(p.nodeTypes, nodeTypes) {
+				addIssue(ConfigLintWarning, "property [%s] is not used by %s nodes, only by [%s]", property, nodeSpec.NodeType, strings.Join(p.nodeTypes, ","))
+			}
+			break
+		}
+	}
+
+	// memory
+	var heap, direct int64
+	var err error
+	if value, found := getJvmOption(jvmOptions, maxHeapOption); found {
+		if heap, err = parseJvmBytes(value); err != nil {
+			addIssue(ConfigLintError, "invalid jvm option [%s%s]", maxHeapOption, value)
+		}
+	}
+	value, directSet := getJvmOption(jvmOptions, maxDirectMemoryOption)
+	if directSet {
+		if direct, err = parseJvmBytes(value); err != nil {
+			addIssue(ConfigLintError, "invalid jvm option [%s%s]", maxDirectMemoryOption, value)
+		}
+	}
+	if memory, ok := getResourceLimit(nodeSpec.Resources, v1.ResourceMemory); ok {
+		// direct memory larger than the container is commonly set to leave it unbounded
+		if direct < memory.Value() && heap+direct > memory.Value() {
+			addIssue(ConfigLintWarning, "heap [%d] and direct memory [%d] exceed the memory [%s] of the resources", heap, direct, memory.String())
+		} else if heap > memory.Value() {
+			addIssue(ConfigLintWarning, "heap [%d] exceeds the memory [%s] of the resources", heap, memory.String())
+		}
+	}
+
+	// processing buffers
+	if ContainsString([]string{historical, broker, indexer}, nodeSpec.NodeType) {
+		severity := ConfigLintError
+		if !directSet {
+			// jvm defaults the direct memory to the heap, but the actual default depends on the jvm so the
+			// issue does not block the rollout
+			direct = heap
+			severity = ConfigLintWarning
+		}
+		if issue := lintProcessingBuffers(nodeSpec, properties, direct); issue != "" {
+			addIssue(severity, "%s", issue)
+		}
+	}
+	return issues
+}
+
+// lintProcessingBuffers returns an issue if the processing buffers of the node do not fit in the direct memory.
+// Buffers sized by druid, or a direct memory not known, are not checked.
+func lintProcessingBuffers(nodeSpec *v1alpha1.DruidNodeSpec, properties string, direct int64) string {
+	value, found := getPropertyValue(properties, processingBufferSizeProperty)
+	if !found || direct <= 0 {
+		return ""
+	}
+	bufferSize, err := parseDruidBytes(value)
+	if err != nil {
+		return fmt.Sprintf("invalid %s [%s]", processingBufferSizeProperty, value)
+	}
+
+	var threads int64
+	if value, found := getPropertyValue(properties, processingNumThreadsProperty); found {
+		if threads, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Sprintf("invalid %s [%s]", processingNumThreadsProperty, value)
+		}
+	} else if cpu, ok := getResourceLimit(nodeSpec.Resources, v1.ResourceCPU); ok {
+		// druid defaults to the number of cores minus one
+		threads = cpu.MilliValue()/1000 - 1
+		if threads < 1 {
+			threads = 1
+		}
+	} else {
+		return ""
+	}
+	mergeBuffers := threads / 4
+	if mergeBuffers < 2 {
+		mergeBuffers = 2
+	}
+	if value, found := getPropertyValue(properties, processingMergeBuffersProperty); found {
+		if mergeBuffers, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Sprintf("invalid %s [%s]", processingMergeBuffersProperty, value)
+		}
+	}
+
+	if required := (threads + mergeBuffers + 1) * bufferSize; required > direct {
+		return fmt.Sprintf("direct memory [%d] is smaller than (numThreads [%d] + numMergeBuffers [%d] + 1) * buffer.sizeBytes [%d] = [%d]",
+			direct, threads, mergeBuffers, bufferSize, required)
+	}
+	return ""
+}
+
+func containsAnyString(slice, values []string) bool {
+	for _, v := range values {
+		if ContainsString(slice, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package druid
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLintDruidConfig(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	if issues := LintDruidConfig(clusterSpec); len(issues) != 0 {
+		t.Errorf("expected no issue on the sample cluster, got %v", issues)
+	}
+
+	clusterSpec.Spec.CommonRuntimeProperties = strings.Replace(clusterSpec.Spec.CommonRuntimeProperties, `"druid-s3-extensions", `, "", 1)
+
+	brokers := clusterSpec.Spec.Nodes["brokers"]
+	brokers.RuntimeProperties += `
+druid.processing.buffer.sizeBytes=500MiB
+druid.processing.numThreads=3
+druid.segmentCache.locations=[]`
+	brokers.ExtraJvmOptions = "-XX:MaxDirectMemorySize=2g"
+	clusterSpec.Spec.Nodes["brokers"] = brokers
+
+	expected := []ConfigLintIssue{
+		{Severity: ConfigLintError, NodeSpec: "brokers", Message: "druid.indexer.logs.type [s3] requires extension [druid-s3-extensions] missing from druid.extensions.loadList"},
+		{Severity: ConfigLintWarning, NodeSpec: "brokers", Message: "property [druid.segmentCache.locations] is not used by broker nodes, only by [historical,indexer,middleManager]"},
+		{Severity: ConfigLintError, NodeSpec: "brokers", Message: "direct memory [2147483648] is smaller than (numThreads [3] + numMergeBuffers [1] + 1) * buffer.sizeBytes [524288000] = [2621440000]"},
+	}
+	var brokerIssues []ConfigLintIssue
+	for _, issue := range LintDruidConfig(clusterSpec) {
+		if issue.NodeSpec == "brokers" {
+			brokerIssues = append(brokerIssues, issue)
+		}
+	}
+	if !reflect.DeepEqual(expected, brokerIssues) {
+		t.Errorf("expected %v, got %v", expected, brokerIssues)
+	}
+
+	if _, err := verifyDruidSpec(clusterSpec); err == nil || !strings.Contains(err.Error(), "Node[brokers] direct memory") {
+		t.Errorf("lint errors must be reported by verifyDruidSpec, got [%v]", err)
+	}
+}
+
+func TestLintProcessingBuffersDefaultDirectMemory(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	brokers := clusterSpec.Spec.Nodes["brokers"]
+	brokers.RuntimeProperties += `
+druid.processing.buffer.sizeBytes=500MiB
+druid.processing.numThreads=3`
+	brokers.JvmOptions = "-Xmx2g"
+	brokers.ExtraJvmOptions = ""
+	clusterSpec.Spec.Nodes["brokers"] = brokers
+
+	expected := ConfigLintIssue{Severity: ConfigLintWarning, NodeSpec: "brokers", Message: "direct memory [2147483648] is smaller than (numThreads [3] + numMergeBuffers [1] + 1) * buffer.sizeBytes [524288000] = [2621440000]"}
+	found := false
+	for _, issue := range LintDruidConfig(clusterSpec) {
+		found = found || issue == expected
+	}
+	if !found {
+		t.Errorf("expected %v", expected)
+	}
+
+	if _, err := verifyDruidSpec(clusterSpec); err != nil && strings.Contains(err.Error(), "Node[brokers] direct memory") {
+		t.Errorf("direct memory defaulted to the heap must not block the rollout, got [%v]", err)
+	}
+}
+
+func TestLintDruidConfigRendersTemplates(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	brokers := clusterSpec.Spec.Nodes["brokers"]
+	brokers.RuntimeProperties += `
+druid.processing.buffer.sizeBytes={{ if eq .NodeType "broker" }}500MiB{{ else }}100MiB{{ end }}
+druid.processing.numThreads=3`
+	brokers.ExtraJvmOptions = "-XX:MaxDirectMemorySize=2g"
+	clusterSpec.Spec.Nodes["brokers"] = brokers
+
+	expected := []ConfigLintIssue{
+		{Severity: ConfigLintError, NodeSpec: "brokers", Message: "direct memory [2147483648] is smaller than (numThreads [3] + numMergeBuffers [1] + 1) * buffer.sizeBytes [524288000] = [2621440000]"},
+	}
+	if issues := LintDruidConfig(clusterSpec); !reflect.DeepEqual(expected, issues) {
+		t.Errorf("expected %v on the rendered properties, got %v", expected, issues)
+	}
+
+	brokers.RuntimeProperties += "\ndruid.server.http.numThreads={{ .Unknown }}"
+	clusterSpec.Spec.Nodes["brokers"] = brokers
+	if issues := LintDruidConfig(clusterSpec); len(issues) != 1 || !strings.Contains(issues[0].String(), "Node[brokers] failed to render template in runtime.properties") {
+		t.Errorf("template failing to render must be reported, got %v", issues)
+	}
+}
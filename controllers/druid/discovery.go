@@ -0,0 +1,191 @@
+package druid
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	discoveryZookeeper         = "zookeeper"
+	discoveryZookeeperWithHTTP = "zookeeperWithHTTP"
+	discoveryKubernetes        = "kubernetes"
+	kubernetesExtension        = "druid-kubernetes-extensions"
+	extensionsLoadListProperty = "druid.extensions.loadList"
+)
+
+func isKubernetesDiscovery(m *v1alpha1.Druid) bool {
+	return m.Spec.Discovery == discoveryKubernetes
+}
+
+// makeDiscoveryRuntimeProperties returns the common properties of the discovery mode, appended to the common
+// runtime properties so that they take precedence.
+func makeDiscoveryRuntimeProperties(m *v1alpha1.Druid) (string, error) {
+	if m.Spec.Discovery != discoveryZookeeperWithHTTP && m.Spec.Discovery != discoveryKubernetes {
+		return "", nil
+	}
+
+	prop := `druid.serverview.type=http
+druid.coordinator.loadqueuepeon.type=http
+druid.indexer.runner.type=httpRemote
+`
+	if !isKubernetesDiscovery(m) {
+		return prop, nil
+	}
+
+	loadList, err := makeExtensionsLoadListProperty(m.Spec.CommonRuntimeProperties, kubernetesExtension)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`%s%sdruid.zk.service.enabled=false
+druid.discovery.type=k8s
+druid.discovery.k8s.clusterIdentifier=%s
+`, prop, loadList, m.Name), nil
+}
+
+// makeExtensionsLoadListProperty returns the loadList property of the properties with the extensions added,
+// or an empty string if they are already loaded. Without loadList, druid loads all the extensions of the extensions
+// directory, so that no loadList is returned either.
+func makeExtensionsLoadListProperty(properties string, extensions ...string) (string, error) {
+	value, found := getPropertyValue(properties, extensionsLoadListProperty)
+	if !found || value == "" {
+		return "", nil
+	}
+	loadList := []string{}
+	if err := json.Unmarshal([]byte(value), &loadList); err != nil {
+		return "", fmt.Errorf("invalid %s [%s] due to [%s]", extensionsLoadListProperty, value, err.Error())
+	}
+
+	added := false
+	for _, ext := range extensions {
+		if !ContainsString(loadList, ext) {
+			loadList = append(loadList, ext)
+			added = true
+		}
+	}
+	if !added {
+		return "", nil
+	}
+
+	bytes, err := json.Marshal(loadList)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s=%s\n", extensionsLoadListProperty, string(bytes)), nil
+}
+
+// getDiscoveryEnv returns the downward API env used by druid-kubernetes-extensions to find its own pod.
+func getDiscoveryEnv(m *v1alpha1.Druid) []v1.EnvVar {
+	if !isKubernetesDiscovery(m) {
+		return nil
+	}
+	return []v1.EnvVar{
+		{Name: "POD_NAME", ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+		{Name: "POD_NAMESPACE", ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.namespace"}}},
+	}
+}
+
+// deployDiscoveryRBAC creates the Role and RoleBinding letting druid pods announce themselves by patching their
+// own labels and annotations, and watch the other pods of the cluster.
+func deployDiscoveryRBAC(sdk client.Client, m *v1alpha1.Druid, roleNames, roleBindingNames map[string]bool, emitEvents EventEmitter) error {
+	if !isKubernetesDiscovery(m) {
+		return nil
+	}
+
+	ls := makeLabelsForDruid(m.Name)
+	if _, err := sdkCreateOrUpdateAsNeeded(sdk,
+		func() (object, error) { return makeDiscoveryRole(m, ls), nil },
+		func() object { return &rbacv1.Role{} },
+		roleIsEquals, noopUpdaterFn, m, roleNames, emitEvents); err != nil {
+		return err
+	}
+	_, err := sdkCreateOrUpdateAsNeeded(sdk,
+		func() (object, error) { return makeDiscoveryRoleBinding(m, ls), nil },
+		func() object { return &rbacv1.RoleBinding{} },
+		roleBindingIsEquals, noopUpdaterFn, m, roleBindingNames, emitEvents)
+	return err
+}
+
+func makeDiscoveryRBACName(m *v1alpha1.Druid) string {
+	return fmt.Sprintf("%s-druid-discovery", m.Name)
+}
+
+func makeDiscoveryRole(m *v1alpha1.Druid, ls map[string]string) *rbacv1.Role {
+	return &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "Role",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      makeDiscoveryRBACName(m),
+			Namespace: m.Namespace,
+			Labels:    ls,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "list", "watch", "patch"},
+			},
+			// leader election of coordinators and overlords
+			{
+				APIGroups: []string{""},
+				Resources: []string{"configmaps"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			},
+		},
+	}
+}
+
+func makeDiscoveryRoleBinding(m *v1alpha1.Druid, ls map[string]string) *rbacv1.RoleBinding {
+	subjects := []rbacv1.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      firstNonEmptyStr(m.Spec.ServiceAccount, "default"),
+			Namespace: m.Namespace,
+		},
+	}
+	// overlords running the kubernetes task runner have their own ServiceAccount when the cluster has none
+	if m.Spec.ServiceAccount == "" {
+		nodes := getNodeSpecs(m)
+		keys := make([]string, 0, len(nodes))
+		for key := range nodes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			nodeSpec := nodes[key]
+			if isKubernetesTaskRunner(&nodeSpec) {
+				subjects = append(subjects, rbacv1.Subject{
+					Kind:      "ServiceAccount",
+					Name:      getTaskRunnerServiceAccountName(m, makeNodeSpecificUniqueString(m, key)),
+					Namespace: m.Namespace,
+				})
+			}
+		}
+	}
+
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "RoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      makeDiscoveryRBACName(m),
+			Namespace: m.Namespace,
+			Labels:    ls,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     makeDiscoveryRBACName(m),
+		},
+		Subjects: subjects,
+	}
+}
@@ -0,0 +1,52 @@
+package druid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMakeExtensionsLoadListProperty(t *testing.T) {
+	prop, err := makeExtensionsLoadListProperty(`druid.extensions.loadList=["druid-kafka-indexing-service"]`, kubernetesExtension)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `druid.extensions.loadList=["druid-kafka-indexing-service","druid-kubernetes-extensions"]` + "\n"
+	if prop != expected {
+		t.Errorf("expected [%s], got [%s]", expected, prop)
+	}
+
+	if prop, _ := makeExtensionsLoadListProperty(`druid.extensions.loadList=["druid-kubernetes-extensions"]`, kubernetesExtension); prop != "" {
+		t.Errorf("loaded extension must not be added again, got [%s]", prop)
+	}
+
+	// druid loads all the extensions without loadList
+	if prop, _ := makeExtensionsLoadListProperty("druid.host=localhost", kubernetesExtension); prop != "" {
+		t.Errorf("loadList must not be added when not set, got [%s]", prop)
+	}
+
+	if _, err := makeExtensionsLoadListProperty("druid.extensions.loadList=[", kubernetesExtension); err == nil {
+		t.Errorf("invalid loadList must be rejected")
+	}
+}
+
+func TestMakeDiscoveryRuntimeProperties(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+
+	if prop, _ := makeDiscoveryRuntimeProperties(clusterSpec); prop != "" {
+		t.Errorf("zookeeper discovery must not add properties, got [%s]", prop)
+	}
+
+	clusterSpec.Spec.Discovery = discoveryZookeeperWithHTTP
+	prop, _ := makeDiscoveryRuntimeProperties(clusterSpec)
+	if !strings.Contains(prop, "druid.serverview.type=http") || strings.Contains(prop, "druid.zk.service.enabled") {
+		t.Errorf("unexpected zookeeperWithHTTP properties [%s]", prop)
+	}
+
+	clusterSpec.Spec.Discovery = discoveryKubernetes
+	prop, _ = makeDiscoveryRuntimeProperties(clusterSpec)
+	for _, expected := range []string{"druid.zk.service.enabled=false", "druid.discovery.type=k8s", "druid.discovery.k8s.clusterIdentifier=" + clusterSpec.Name, kubernetesExtension} {
+		if !strings.Contains(prop, expected) {
+			t.Errorf("expected [%s] in kubernetes properties [%s]", expected, prop)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package druid
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	druidAPITimeout          = 30 * time.Second
+	defaultAuthUsernameKey   = "username"
+	defaultAuthPasswordKey   = "password"
+	defaultAuthenticatorName = "MyBasicMetadataAuthenticator"
+	defaultAuthorizerName    = "MyBasicMetadataAuthorizer"
+)
+
+// druidAPIClient calls the HTTP APIs of a druid node, through the first service of its node spec or on its pod.
+type druidAPIClient struct {
+	baseURL    string
+	httpClient *http.Client
+	// basic auth credentials, not sent if username is empty
+	username string
+	password string
+}
+
+// druidAPIError is returned for non 2xx responses of druid APIs.
+type druidAPIError struct {
+	method     string
+	path       string
+	statusCode int
+	body       string
+}
+
+func (e *druidAPIError) Error() string {
+	return fmt.Sprintf("%s %s failed with status [%d]: %s", e.method, e.path, e.statusCode, e.body)
+}
+
+// isDruidAPINotFound returns true if the druid API responded with 404.
+func isDruidAPINotFound(err error) bool {
+	apiErr, ok := err.(*druidAPIError)
+	return ok && apiErr.statusCode == http.StatusNotFound
+}
+
+// newDruidAPIClient returns a client for the first node spec of given node type, in the order of node spec keys.
+// In case no overlord node spec exists, overlord APIs are called on the coordinator, running as overlord.
+// Requests are authenticated with the admin credentials of spec.auth if set.
+func newDruidAPIClient(sdk client.Client, m *v1alpha1.Druid, nodeType string) (*druidAPIClient, error) {
+	nodes := getNodeSpecs(m)
+	keys := make([]string, 0, len(nodes))
+	for key := range nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		nodeSpec := nodes[key]
+		if nodeSpec.NodeType != nodeType {
+			continue
+		}
+
+		services := firstNonNilValue(nodeSpec.Services, m.Spec.Services).([]v1.Service)
+		if len(services) == 0 {
+			return nil, fmt.Errorf("node spec [%s] of CR [%s] has no service to reach the %s API", key, m.Name, nodeType)
+		}
+
+		nodeSpecUniqueStr := makeNodeSpecificUniqueString(m, key)
+		serviceName := getServiceName(services[0].ObjectMeta.Name, nodeSpecUniqueStr)
+		return newDruidHostAPIClient(sdk, m, nodeSpecUniqueStr, fmt.Sprintf("%s.%s.svc", serviceName, m.Namespace), nodeSpec.DruidPort, "")
+	}
+
+	if nodeType == overlord {
+		return newDruidAPIClient(sdk, m, coordinator)
+	}
+	return nil, fmt.Errorf("no node spec of type [%s] found in CR [%s]", nodeType, m.Name)
+}
+
+// newDruidHostAPIClient returns a client for the druid node of the node spec listening on host and port.
+// serverName is verified against the certificate of the node spec if TLS is enabled, defaults to host.
+func newDruidHostAPIClient(sdk client.Client, m *v1alpha1.Druid, nodeSpecUniqueStr, host string, port int32, serverName string) (*druidAPIClient, error) {
+	c := &druidAPIClient{
+		baseURL:    fmt.Sprintf("http://%s:%d", host, port),
+		httpClient: &http.Client{Timeout: druidAPITimeout},
+	}
+	if m.Spec.TLS != nil {
+		rootCAs, err := getDruidTLSRootCAs(sdk, m, nodeSpecUniqueStr)
+		if err != nil {
+			return nil, err
+		}
+		c.baseURL = fmt.Sprintf("https://%s:%d", host, port)
+		c.httpClient.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: rootCAs, ServerName: serverName}}
+	}
+	if m.Spec.Auth != nil {
+		username, password, err := getDruidAuthCredentials(sdk, m)
+		if err != nil {
+			return nil, err
+		}
+		c.username, c.password = username, password
+	}
+	return c, nil
+}
+
+// do sends the request and returns the response body, an error is returned for non 2xx responses.
+// body is sent as is if it is a string, else it is marshalled to json. Response is unmarshalled into out if not nil.
+func (c *druidAPIClient) do(method, path string, body, out interface{}) ([]byte, error) {
+	var reqBody io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reqBody = bytes.NewBufferString(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewBuffer(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return respBody, &druidAPIError{method: method, path: path, statusCode: resp.StatusCode, body: string(respBody)}
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return respBody, fmt.Errorf("failed to unmarshal response of %s %s due to [%s]", method, path, err.Error())
+		}
+	}
+	return respBody, nil
+}
+
+// getDruidAuthCredentials returns the admin username and password from the Secret of spec.auth.
+func getDruidAuthCredentials(sdk client.Client, m *v1alpha1.Druid) (string, string, error) {
+	secret := &v1.Secret{}
+	if err := sdk.Get(context.TODO(), *namespacedName(m.Spec.Auth.SecretRef.Name, m.Namespace), secret); err != nil {
+		return "", "", fmt.Errorf("failed to get auth Secret [%s] due to [%s]", m.Spec.Auth.SecretRef.Name, err.Error())
+	}
+
+	usernameKey := firstNonEmptyStr(m.Spec.Auth.UsernameKey, defaultAuthUsernameKey)
+	passwordKey := firstNonEmptyStr(m.Spec.Auth.PasswordKey, defaultAuthPasswordKey)
+	username, ok := secret.Data[usernameKey]
+	if !ok {
+		return "", "", fmt.Errorf("auth Secret [%s] has no key [%s]", m.Spec.Auth.SecretRef.Name, usernameKey)
+	}
+	password, ok := secret.Data[passwordKey]
+	if !ok {
+		return "", "", fmt.Errorf("auth Secret [%s] has no key [%s]", m.Spec.Auth.SecretRef.Name, passwordKey)
+	}
+	return string(username), string(password), nil
+}
+
+// getDruidTLSRootCAs returns the CA of the certificate of the node spec, to verify druid nodes serving TLS.
+func getDruidTLSRootCAs(sdk client.Client, m *v1alpha1.Druid, nodeSpecUniqueStr string) (*x509.CertPool, error) {
+	secret := &v1.Secret{}
+	if err := sdk.Get(context.TODO(), *namespacedName(makeTLSSecretName(nodeSpecUniqueStr), m.Namespace), secret); err != nil {
+		return nil, fmt.Errorf("failed to get TLS Secret [%s] due to [%s]", makeTLSSecretName(nodeSpecUniqueStr), err.Error())
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(secret.Data[tlsCAKey]) {
+		return nil, fmt.Errorf("TLS Secret [%s] has no valid [%s]", makeTLSSecretName(nodeSpecUniqueStr), tlsCAKey)
+	}
+	return pool, nil
+}
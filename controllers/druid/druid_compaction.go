@@ -0,0 +1,167 @@
+package druid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncDruidCompaction applies the compaction configs that differ from the coordinator ones, prunes undeclared
+// configs if requested and mirrors the compaction status of the declared datasources.
+func syncDruidCompaction(sdk client.Client, dc *v1alpha1.DruidCompaction, emitEvents EventEmitter) error {
+	drd := &v1alpha1.Druid{}
+	if err := sdk.Get(context.TODO(), *namespacedName(dc.Spec.ClusterRef, dc.Namespace), drd); err != nil {
+		if apierrors.IsNotFound(err) {
+			return setDruidCompactionNotSynced(sdk, dc, fmt.Sprintf("Druid CR [%s] not found", dc.Spec.ClusterRef), emitEvents)
+		}
+		return err
+	}
+
+	desired := map[string]map[string]interface{}{}
+	for _, cfg := range dc.Spec.Configs {
+		config, err := makeCompactionConfig(cfg)
+		if err != nil {
+			return setDruidCompactionNotSynced(sdk, dc, err.Error(), emitEvents)
+		}
+		desired[cfg.DataSource] = config
+	}
+
+	c, err := newDruidAPIClient(sdk, drd, coordinator)
+	if err != nil {
+		return setDruidCompactionNotSynced(sdk, dc, err.Error(), emitEvents)
+	}
+
+	current := struct {
+		CompactionConfigs []map[string]interface{} `json:"compactionConfigs"`
+	}{}
+	if _, err := c.do(http.MethodGet, "/druid/coordinator/v1/config/compaction", nil, &current); err != nil {
+		return err
+	}
+	currentByDataSource := map[string]interface{}{}
+	for _, config := range current.CompactionConfigs {
+		if ds, ok := config["dataSource"].(string); ok {
+			currentByDataSource[ds] = config
+		}
+	}
+
+	status := *dc.Status.DeepCopy()
+	status.SpecHashes = map[string]string{}
+
+	for ds, config := range desired {
+		desiredValue, err := toJSONValue(config)
+		if err != nil {
+			return err
+		}
+		// the coordinator keeps the fields removed from the spec unless the whole config is posted again
+		hash, err := getJSONHash(config)
+		if err != nil {
+			return err
+		}
+		status.SpecHashes[ds] = hash
+		if hash == dc.Status.SpecHashes[ds] && isJSONSubset(desiredValue, currentByDataSource[ds]) {
+			continue
+		}
+		if _, err := c.do(http.MethodPost, "/druid/coordinator/v1/config/compaction", config, nil); err != nil {
+			return setDruidCompactionNotSynced(sdk, dc, err.Error(), emitEvents)
+		}
+		now := metav1.Now()
+		status.LastSyncTime = &now
+
+		msg := fmt.Sprintf("Applied compaction config of datasource [%s]", ds)
+		logger.Info(msg, "name", dc.Name, "namespace", dc.Namespace)
+		emitEvents.EmitEventGeneric(dc, "DruidCompactionSynced", msg, nil)
+	}
+
+	if dc.Spec.Prune {
+		declared, err := getDeclaredCompactionDataSources(sdk, dc)
+		if err != nil {
+			return err
+		}
+		for ds := range currentByDataSource {
+			if declared[ds] {
+				continue
+			}
+			if _, err := c.do(http.MethodDelete, fmt.Sprintf("/druid/coordinator/v1/config/compaction/%s", url.PathEscape(ds)), nil, nil); err != nil && !isDruidAPINotFound(err) {
+				return setDruidCompactionNotSynced(sdk, dc, err.Error(), emitEvents)
+			}
+			msg := fmt.Sprintf("Pruned compaction config of undeclared datasource [%s]", ds)
+			logger.Info(msg, "name", dc.Name, "namespace", dc.Namespace)
+			emitEvents.EmitEventGeneric(dc, "DruidCompactionPruned", msg, nil)
+		}
+	}
+
+	compactionStatus := struct {
+		LatestStatus []struct {
+			DataSource string `json:"dataSource"`
+			v1alpha1.DruidCompactionDataSourceStatus
+		} `json:"latestStatus"`
+	}{}
+	if _, err := c.do(http.MethodGet, "/druid/coordinator/v1/compaction/status", nil, &compactionStatus); err != nil {
+		return err
+	}
+	status.DataSources = map[string]v1alpha1.DruidCompactionDataSourceStatus{}
+	for _, s := range compactionStatus.LatestStatus {
+		if _, ok := desired[s.DataSource]; ok {
+			status.DataSources[s.DataSource] = s.DruidCompactionDataSourceStatus
+		}
+	}
+
+	status.Synced = true
+	status.Message = "Compaction configs in sync with the coordinator"
+	if reflect.DeepEqual(status, dc.Status) {
+		return nil
+	}
+	return druidObjectStatusPatcher(sdk, dc, status)
+}
+
+// makeCompactionConfig returns the compaction config json of the datasource, with the dataSource set.
+func makeCompactionConfig(cfg v1alpha1.DruidCompactionConfig) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	if cfg.Config != "" {
+		if err := json.Unmarshal([]byte(cfg.Config), &config); err != nil {
+			return nil, fmt.Errorf("invalid compaction config json of datasource [%s] due to [%s]", cfg.DataSource, err.Error())
+		}
+	}
+	config["dataSource"] = cfg.DataSource
+	return config, nil
+}
+
+// getDeclaredCompactionDataSources returns the datasources declared by all the DruidCompactions of the cluster.
+func getDeclaredCompactionDataSources(sdk client.Client, dc *v1alpha1.DruidCompaction) (map[string]bool, error) {
+	list := &v1alpha1.DruidCompactionList{}
+	if err := sdk.List(context.TODO(), list, client.InNamespace(dc.Namespace)); err != nil {
+		return nil, err
+	}
+
+	declared := map[string]bool{}
+	for _, item := range list.Items {
+		if item.Spec.ClusterRef != dc.Spec.ClusterRef || item.DeletionTimestamp != nil {
+			continue
+		}
+		for _, cfg := range item.Spec.Configs {
+			declared[cfg.DataSource] = true
+		}
+	}
+	return declared, nil
+}
+
+func setDruidCompactionNotSynced(sdk client.Client, dc *v1alpha1.DruidCompaction, msg string, emitEvents EventEmitter) error {
+	if !dc.Status.Synced && dc.Status.Message == msg {
+		return nil
+	}
+	emitEvents.EmitEventGeneric(dc, "DruidCompactionSyncFail", "", errors.New(msg))
+
+	status := *dc.Status.DeepCopy()
+	status.Synced = false
+	status.Message = msg
+	return druidObjectStatusPatcher(sdk, dc, status)
+}
@@ -0,0 +1,22 @@
+package druid
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	druidv1alpha1 "github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+// DruidCompactionReconciler reconciles a DruidCompaction object
+type DruidCompactionReconciler struct {
+	druidObjectReconciler[*druidv1alpha1.DruidCompaction]
+}
+
+func NewDruidCompactionReconciler(mgr ctrl.Manager) *DruidCompactionReconciler {
+	return &DruidCompactionReconciler{
+		druidObjectReconciler: newDruidObjectReconciler(mgr, "DruidCompaction",
+			func() *druidv1alpha1.DruidCompaction { return &druidv1alpha1.DruidCompaction{} }, unfinishedSync(syncDruidCompaction)),
+	}
+}
+
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidcompactions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidcompactions/status,verbs=get;update;patch
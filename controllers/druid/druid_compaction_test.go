@@ -0,0 +1,29 @@
+package druid
+
+import (
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+func TestMakeCompactionConfig(t *testing.T) {
+	config, err := makeCompactionConfig(v1alpha1.DruidCompactionConfig{
+		DataSource: "wikipedia",
+		Config:     `{"dataSource":"other","skipOffsetFromLatest":"P1D"}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config["dataSource"] != "wikipedia" || config["skipOffsetFromLatest"] != "P1D" {
+		t.Errorf("unexpected config %v", config)
+	}
+
+	config, err = makeCompactionConfig(v1alpha1.DruidCompactionConfig{DataSource: "wikipedia"})
+	if err != nil || len(config) != 1 {
+		t.Errorf("unexpected config %v [%v]", config, err)
+	}
+
+	if _, err := makeCompactionConfig(v1alpha1.DruidCompactionConfig{DataSource: "wikipedia", Config: "{"}); err == nil {
+		t.Errorf("invalid json must be rejected")
+	}
+}
@@ -5,15 +5,19 @@ import (
 	"os"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	druidv1alpha1 "github.com/druid-io/druid-operator/apis/druid/v1alpha1"
 )
@@ -74,6 +78,10 @@ func (r *DruidReconciler) Reconcile(ctx context.Context, request reconcile.Reque
 func (r *DruidReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&druidv1alpha1.Druid{}).
+		// only the metadata of the ConfigMaps and Secrets of the namespace is watched to trigger reconciles, the
+		// content of the configFrom ones being hashed on reconcile
+		Watches(&source.Kind{Type: &v1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(makeConfigFromRequestsFn(r.Client, configFromConfigMap)), builder.OnlyMetadata).
+		Watches(&source.Kind{Type: &v1.Secret{}}, handler.EnqueueRequestsFromMapFunc(makeConfigFromRequestsFn(r.Client, configFromSecret)), builder.OnlyMetadata).
 		WithEventFilter(GenericPredicates{}).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: getMaxConcurrentReconciles(),
@@ -0,0 +1,167 @@
+package druid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	lookupFinalizerName = "lookup.finalizers.druid.apache.org"
+	defaultLookupTier   = "__default"
+	// lookup versions are compared lexicographically by the coordinator, a sortable timestamp keeps them increasing.
+	lookupVersionLayout = "2006-01-02T15:04:05.000Z"
+)
+
+// syncDruidLookup submits the lookup to the coordinator when its extractor factory changes or drifts,
+// and mirrors the load status of the lookup in the tier. Lookup is deleted on deletion of the DruidLookup.
+func syncDruidLookup(sdk client.Client, lookup *v1alpha1.DruidLookup, emitEvents EventEmitter) error {
+	drd := &v1alpha1.Druid{}
+	if err := sdk.Get(context.TODO(), *namespacedName(lookup.Spec.ClusterRef, lookup.Namespace), drd); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if lookup.DeletionTimestamp != nil {
+			return removeObjectFinalizer(sdk, lookup, lookupFinalizerName)
+		}
+		return setDruidLookupMessage(sdk, lookup, fmt.Sprintf("Druid CR [%s] not found", lookup.Spec.ClusterRef), emitEvents)
+	}
+
+	c, err := newDruidAPIClient(sdk, drd, coordinator)
+	if err != nil {
+		if lookup.DeletionTimestamp != nil {
+			return removeObjectFinalizer(sdk, lookup, lookupFinalizerName)
+		}
+		return setDruidLookupMessage(sdk, lookup, err.Error(), emitEvents)
+	}
+
+	path := fmt.Sprintf("/druid/coordinator/v1/lookups/config/%s/%s",
+		url.PathEscape(firstNonEmptyStr(lookup.Spec.Tier, defaultLookupTier)), url.PathEscape(firstNonEmptyStr(lookup.Spec.Name, lookup.Name)))
+
+	if lookup.DeletionTimestamp != nil {
+		if _, err := c.do(http.MethodDelete, path, nil, nil); err != nil && !isDruidAPINotFound(err) {
+			return err
+		}
+		return removeObjectFinalizer(sdk, lookup, lookupFinalizerName)
+	}
+
+	if err := addObjectFinalizer(sdk, lookup, lookupFinalizerName); err != nil {
+		return err
+	}
+
+	factory, err := makeLookupExtractorFactory(sdk, lookup)
+	if err != nil {
+		return setDruidLookupMessage(sdk, lookup, err.Error(), emitEvents)
+	}
+	hash, err := getJSONHash(factory)
+	if err != nil {
+		return err
+	}
+
+	current := struct {
+		Version                string      `json:"version"`
+		LookupExtractorFactory interface{} `json:"lookupExtractorFactory"`
+	}{}
+	_, err = c.do(http.MethodGet, path, nil, &current)
+	if err != nil && !isDruidAPINotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	status := *lookup.Status.DeepCopy()
+
+	desired, err := toJSONValue(factory)
+	if err != nil {
+		return err
+	}
+
+	if !exists || hash != status.SpecHash || current.Version != status.Version || !isJSONSubset(desired, current.LookupExtractorFactory) {
+		if !exists {
+			// initializes the lookup config of the coordinator, no-op if already initialized
+			if _, err := c.do(http.MethodPost, "/druid/coordinator/v1/lookups/config", map[string]interface{}{}, nil); err != nil {
+				return setDruidLookupMessage(sdk, lookup, err.Error(), emitEvents)
+			}
+		}
+
+		version := time.Now().UTC().Format(lookupVersionLayout)
+		if _, err := c.do(http.MethodPost, path, map[string]interface{}{
+			"version":                version,
+			"lookupExtractorFactory": factory,
+		}, nil); err != nil {
+			return setDruidLookupMessage(sdk, lookup, err.Error(), emitEvents)
+		}
+
+		status.Version = version
+		status.SpecHash = hash
+		status.Loaded = false
+		status.Message = "Lookup submitted to the coordinator"
+
+		msg := fmt.Sprintf("Submitted lookup [%s] version [%s]", path, version)
+		logger.Info(msg, "name", lookup.Name, "namespace", lookup.Namespace)
+		emitEvents.EmitEventGeneric(lookup, "DruidLookupSubmitted", msg, nil)
+	} else {
+		loadStatus := struct {
+			Loaded       bool     `json:"loaded"`
+			PendingNodes []string `json:"pendingNodes"`
+		}{}
+		statusPath := fmt.Sprintf("/druid/coordinator/v1/lookups/status/%s/%s",
+			url.PathEscape(firstNonEmptyStr(lookup.Spec.Tier, defaultLookupTier)), url.PathEscape(firstNonEmptyStr(lookup.Spec.Name, lookup.Name)))
+		if _, err := c.do(http.MethodGet, statusPath+"?detailed=true", nil, &loadStatus); err != nil && !isDruidAPINotFound(err) {
+			return err
+		}
+		status.Loaded = loadStatus.Loaded
+		status.PendingNodes = loadStatus.PendingNodes
+		status.Message = "Lookup in sync with the coordinator"
+	}
+
+	if reflect.DeepEqual(status, lookup.Status) {
+		return nil
+	}
+	return druidObjectStatusPatcher(sdk, lookup, status)
+}
+
+// makeLookupExtractorFactory returns the lookup extractor factory json, or a map lookup built from the ConfigMap data.
+func makeLookupExtractorFactory(sdk client.Client, lookup *v1alpha1.DruidLookup) (map[string]interface{}, error) {
+	if (lookup.Spec.LookupExtractorFactory == "") == (lookup.Spec.ConfigMapRef == nil) {
+		return nil, errors.New("exactly one of lookupExtractorFactory and configMapRef must be set")
+	}
+
+	if lookup.Spec.ConfigMapRef != nil {
+		cm := &v1.ConfigMap{}
+		if err := sdk.Get(context.TODO(), *namespacedName(lookup.Spec.ConfigMapRef.Name, lookup.Namespace), cm); err != nil {
+			return nil, fmt.Errorf("failed to get ConfigMap [%s] due to [%s]", lookup.Spec.ConfigMapRef.Name, err.Error())
+		}
+		data := map[string]string{}
+		for k, v := range cm.Data {
+			data[k] = v
+		}
+		return map[string]interface{}{"type": "map", "map": data}, nil
+	}
+
+	factory := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(lookup.Spec.LookupExtractorFactory), &factory); err != nil {
+		return nil, fmt.Errorf("invalid lookupExtractorFactory json due to [%s]", err.Error())
+	}
+	return factory, nil
+}
+
+func setDruidLookupMessage(sdk client.Client, lookup *v1alpha1.DruidLookup, msg string, emitEvents EventEmitter) error {
+	if lookup.Status.Message == msg {
+		return nil
+	}
+	emitEvents.EmitEventGeneric(lookup, "DruidLookupSyncFail", "", errors.New(msg))
+
+	status := *lookup.Status.DeepCopy()
+	status.Message = msg
+	return druidObjectStatusPatcher(sdk, lookup, status)
+}
@@ -0,0 +1,22 @@
+package druid
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	druidv1alpha1 "github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+// DruidLookupReconciler reconciles a DruidLookup object
+type DruidLookupReconciler struct {
+	druidObjectReconciler[*druidv1alpha1.DruidLookup]
+}
+
+func NewDruidLookupReconciler(mgr ctrl.Manager) *DruidLookupReconciler {
+	return &DruidLookupReconciler{
+		druidObjectReconciler: newDruidObjectReconciler(mgr, "DruidLookup",
+			func() *druidv1alpha1.DruidLookup { return &druidv1alpha1.DruidLookup{} }, unfinishedSync(syncDruidLookup)),
+	}
+}
+
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidlookups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidlookups/status,verbs=get;update;patch
@@ -0,0 +1,35 @@
+package druid
+
+import (
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestMakeLookupExtractorFactory(t *testing.T) {
+	lookup := &v1alpha1.DruidLookup{
+		Spec: v1alpha1.DruidLookupSpec{
+			ClusterRef:             "cluster",
+			LookupExtractorFactory: `{"type":"map","map":{"us":"United States"}}`,
+		},
+	}
+	factory, err := makeLookupExtractorFactory(nil, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if factory["type"] != "map" || factory["map"].(map[string]interface{})["us"] != "United States" {
+		t.Errorf("unexpected factory %v", factory)
+	}
+
+	lookup.Spec.ConfigMapRef = &v1.LocalObjectReference{Name: "countries"}
+	if _, err := makeLookupExtractorFactory(nil, lookup); err == nil {
+		t.Errorf("lookupExtractorFactory and configMapRef must be exclusive")
+	}
+
+	lookup.Spec.ConfigMapRef = nil
+	lookup.Spec.LookupExtractorFactory = "{"
+	if _, err := makeLookupExtractorFactory(nil, lookup); err == nil {
+		t.Errorf("invalid json must be rejected")
+	}
+}
@@ -0,0 +1,74 @@
+package druid
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// druidObjectReconciler reconciles the druid operator CRs other than Druid by running a sync function on them,
+// the CRs are requeued after ReconcileWait until the sync function reports them finished.
+type druidObjectReconciler[T client.Object] struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	// wait between two syncs of the CR, defaults to 10s
+	ReconcileWait time.Duration
+	Recorder      record.EventRecorder
+
+	newObject func() T
+	sync      func(sdk client.Client, obj T, emitEvent EventEmitter) (bool, error)
+}
+
+func newDruidObjectReconciler[T client.Object](mgr ctrl.Manager, name string, newObject func() T, sync func(client.Client, T, EventEmitter) (bool, error)) druidObjectReconciler[T] {
+	return druidObjectReconciler[T]{
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName(name),
+		Scheme:        mgr.GetScheme(),
+		ReconcileWait: LookupReconcileTime(),
+		Recorder:      mgr.GetEventRecorderFor("druid-operator"),
+		newObject:     newObject,
+		sync:          sync,
+	}
+}
+
+// unfinishedSync adapts the sync functions of CRs kept in sync with druid, which are never finished.
+func unfinishedSync[T client.Object](sync func(client.Client, T, EventEmitter) error) func(client.Client, T, EventEmitter) (bool, error) {
+	return func(sdk client.Client, obj T, emitEvent EventEmitter) (bool, error) {
+		return false, sync(sdk, obj, emitEvent)
+	}
+}
+
+func (r *druidObjectReconciler[T]) Reconcile(ctx context.Context, request reconcile.Request) (ctrl.Result, error) {
+	instance := r.newObject()
+	err := r.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	var emitEvent EventEmitter = EmitEventFuncs{r.Recorder}
+
+	if finished, err := r.sync(r.Client, instance, emitEvent); err != nil {
+		return ctrl.Result{}, err
+	} else if finished {
+		return ctrl.Result{}, nil
+	} else {
+		return ctrl.Result{RequeueAfter: r.ReconcileWait}, nil
+	}
+}
+
+func (r *druidObjectReconciler[T]) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(r.newObject()).
+		Complete(r)
+}
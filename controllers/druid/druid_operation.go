@@ -0,0 +1,267 @@
+package druid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maximum length of the druid API response recorded in the operation status
+const druidOperationMaxResultLength = 1024
+
+// runDruidOperation moves the operation through its phases, returns true once the operation is finished.
+// Only one operation runs per druid cluster at a time, pending operations are started in creation order.
+func runDruidOperation(sdk client.Client, op *v1alpha1.DruidOperation, emitEvents EventEmitter) (bool, error) {
+	if isDruidOperationFinished(op) {
+		return true, nil
+	}
+
+	drd := &v1alpha1.Druid{}
+	if err := sdk.Get(context.TODO(), *namespacedName(op.Spec.ClusterRef, op.Namespace), drd); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, finishDruidOperation(sdk, op, v1alpha1.DruidOperationFailed, fmt.Sprintf("Druid CR [%s] not found", op.Spec.ClusterRef), "", emitEvents)
+		}
+		return false, err
+	}
+
+	if op.Status.Phase != v1alpha1.DruidOperationRunning {
+		opList := &v1alpha1.DruidOperationList{}
+		if err := sdk.List(context.TODO(), opList, client.InNamespace(op.Namespace)); err != nil {
+			return false, err
+		}
+
+		if !isDruidOperationRunnable(op, opList.Items) {
+			if op.Status.Phase == v1alpha1.DruidOperationPending {
+				return false, nil
+			}
+			return false, druidObjectStatusPatcher(sdk, op, v1alpha1.DruidOperationStatus{
+				Phase:   v1alpha1.DruidOperationPending,
+				Message: fmt.Sprintf("Waiting for other operations on Druid CR [%s] to finish", drd.Name),
+			})
+		}
+
+		if err := verifyDruidOperationSpec(op, drd); err != nil {
+			return true, finishDruidOperation(sdk, op, v1alpha1.DruidOperationFailed, err.Error(), "", emitEvents)
+		}
+
+		now := metav1.Now()
+		if err := druidObjectStatusPatcher(sdk, op, v1alpha1.DruidOperationStatus{
+			Phase:     v1alpha1.DruidOperationRunning,
+			StartTime: &now,
+			Message:   fmt.Sprintf("Running %s on Druid CR [%s]", op.Spec.Action, drd.Name),
+		}); err != nil {
+			return false, err
+		}
+		emitEvents.EmitEventGeneric(op, "DruidOperationStarted", op.Status.Message, nil)
+	}
+
+	done, result, err := executeDruidOperation(sdk, op, drd, emitEvents)
+	var retryErr *druidOperationRetryError
+	if errors.As(err, &retryErr) {
+		emitEvents.EmitEventGeneric(op, "DruidOperationStepFail", "", err)
+		return false, nil
+	}
+	if err != nil {
+		return true, finishDruidOperation(sdk, op, v1alpha1.DruidOperationFailed, err.Error(), result, emitEvents)
+	}
+	if done {
+		return true, finishDruidOperation(sdk, op, v1alpha1.DruidOperationSucceeded, fmt.Sprintf("%s succeeded", op.Spec.Action), result, emitEvents)
+	}
+	return false, nil
+}
+
+// druidOperationRetryError is a transient failure of a step of the operation, the step is run again on next reconcile
+// instead of failing the operation.
+type druidOperationRetryError struct {
+	err error
+}
+
+func (e *druidOperationRetryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *druidOperationRetryError) Unwrap() error {
+	return e.err
+}
+
+// executeDruidOperation runs one step of the operation, returns true once the action is complete.
+func executeDruidOperation(sdk client.Client, op *v1alpha1.DruidOperation, drd *v1alpha1.Druid, emitEvents EventEmitter) (bool, string, error) {
+	switch op.Spec.Action {
+	case v1alpha1.DruidOperationRestartNode:
+		return restartDruidNode(sdk, op, drd, emitEvents)
+	case v1alpha1.DruidOperationReloadSegments:
+		path := fmt.Sprintf("/druid/coordinator/v1/datasources/%s", url.PathEscape(op.Spec.DataSource))
+		if op.Spec.Interval == "" {
+			return callDruidAPIOnce(sdk, drd, coordinator, path, nil)
+		}
+		return callDruidAPIOnce(sdk, drd, coordinator, path+"/markUsed", map[string]string{"interval": op.Spec.Interval})
+	case v1alpha1.DruidOperationKillTask:
+		return callDruidAPIOnce(sdk, drd, overlord, fmt.Sprintf("/druid/indexer/v1/task/%s/shutdown", url.PathEscape(op.Spec.TaskID)), nil)
+	case v1alpha1.DruidOperationTriggerCompaction:
+		return callDruidAPIOnce(sdk, drd, coordinator, "/druid/coordinator/v1/compaction/compact", nil)
+	case v1alpha1.DruidOperationReplacePVC:
+		return replaceDruidPVC(sdk, op, drd, emitEvents)
+	default:
+		return false, "", fmt.Errorf("unsupported action [%s]", op.Spec.Action)
+	}
+}
+
+func callDruidAPIOnce(sdk client.Client, drd *v1alpha1.Druid, nodeType, path string, body interface{}) (bool, string, error) {
+	c, err := newDruidAPIClient(sdk, drd, nodeType)
+	if err != nil {
+		return false, "", err
+	}
+	resp, err := c.do(http.MethodPost, path, body, nil)
+	return true, truncateDruidOperationResult(string(resp)), err
+}
+
+// restartDruidNode stamps restartedAt on the node spec of the Druid CR, the Druid reconcile then rolls out the pods.
+// Action is complete once the workload of the node spec is fully deployed with the stamp.
+func restartDruidNode(sdk client.Client, op *v1alpha1.DruidOperation, drd *v1alpha1.Druid, emitEvents EventEmitter) (bool, string, error) {
+	key := op.Spec.NodeSpecKey
+	nodeSpec := getNodeSpecs(drd)[key]
+	restartedAt := op.Status.StartTime.UTC().Format(time.RFC3339)
+
+	if nodeSpec.RestartedAt != restartedAt {
+		patchBytes, err := json.Marshal(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"nodes": map[string]interface{}{
+					key: map[string]interface{}{"restartedAt": restartedAt},
+				},
+			},
+		})
+		if err != nil {
+			return false, "", err
+		}
+		return false, "", writers.Patch(context.TODO(), sdk, drd, drd, false, client.RawPatch(types.MergePatchType, patchBytes), emitEvents)
+	}
+
+	nodeSpecUniqueStr := makeNodeSpecificUniqueString(drd, key)
+	var emptyObjFn func() object
+	if nodeSpec.Kind == "Deployment" {
+		emptyObjFn = func() object { return makeDeploymentEmptyObj() }
+		if isBlueGreen(&nodeSpec) {
+			nodeSpecUniqueStr = makeBlueGreenDeploymentName(nodeSpecUniqueStr, getBlueGreenActiveColor(drd, key))
+		}
+	} else {
+		emptyObjFn = func() object { return makeStatefulSetEmptyObj() }
+	}
+
+	obj, err := readers.Get(context.TODO(), sdk, nodeSpecUniqueStr, drd, emptyObjFn, emitEvents)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, "", nil
+		}
+		return false, "", err
+	}
+
+	// wait until the workload controller has seen the stamped pod template
+	switch w := obj.(type) {
+	case *appsv1.StatefulSet:
+		if w.Spec.Template.Annotations[nodeRestartedAtAnnotation] != restartedAt || w.Status.ObservedGeneration != w.Generation {
+			return false, "", nil
+		}
+	case *appsv1.Deployment:
+		if w.Spec.Template.Annotations[nodeRestartedAtAnnotation] != restartedAt || w.Status.ObservedGeneration != w.Generation {
+			return false, "", nil
+		}
+	}
+
+	done, err := isObjFullyDeployed(sdk, nodeSpec, nodeSpecUniqueStr, drd, emptyObjFn, emitEvents)
+	if err != nil || !done {
+		return false, "", err
+	}
+	return true, fmt.Sprintf("pods of node [%s] restarted at [%s]", key, restartedAt), nil
+}
+
+// isDruidOperationRunnable returns true if no other operation of the same cluster is running
+// and no older operation of the same cluster is waiting.
+func isDruidOperationRunnable(op *v1alpha1.DruidOperation, ops []v1alpha1.DruidOperation) bool {
+	for i := range ops {
+		other := &ops[i]
+		if other.Name == op.Name || other.Spec.ClusterRef != op.Spec.ClusterRef || isDruidOperationFinished(other) {
+			continue
+		}
+		if other.Status.Phase == v1alpha1.DruidOperationRunning {
+			return false
+		}
+		if other.CreationTimestamp.Before(&op.CreationTimestamp) ||
+			(other.CreationTimestamp.Equal(&op.CreationTimestamp) && other.Name < op.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDruidOperationFinished(op *v1alpha1.DruidOperation) bool {
+	return op.Status.Phase == v1alpha1.DruidOperationSucceeded || op.Status.Phase == v1alpha1.DruidOperationFailed
+}
+
+func verifyDruidOperationSpec(op *v1alpha1.DruidOperation, drd *v1alpha1.Druid) error {
+	switch op.Spec.Action {
+	case v1alpha1.DruidOperationRestartNode:
+		if _, ok := drd.Spec.Nodes[op.Spec.NodeSpecKey]; !ok {
+			return fmt.Errorf("nodeSpecKey [%s] not found in Druid CR [%s]", op.Spec.NodeSpecKey, drd.Name)
+		}
+	case v1alpha1.DruidOperationReplacePVC:
+		nodeSpec, ok := getNodeSpecs(drd)[op.Spec.NodeSpecKey]
+		if !ok {
+			return fmt.Errorf("nodeSpecKey [%s] not found in Druid CR [%s]", op.Spec.NodeSpecKey, drd.Name)
+		}
+		if nodeSpec.Kind == "Deployment" {
+			return fmt.Errorf("node spec [%s] is not a StatefulSet", op.Spec.NodeSpecKey)
+		}
+		if op.Spec.Ordinal == nil || *op.Spec.Ordinal >= nodeSpec.Replicas {
+			return fmt.Errorf("ordinal must be set and lower than the replicas of node spec [%s]", op.Spec.NodeSpecKey)
+		}
+	case v1alpha1.DruidOperationReloadSegments:
+		if op.Spec.DataSource == "" {
+			return errors.New("dataSource is required by ReloadSegments")
+		}
+	case v1alpha1.DruidOperationKillTask:
+		if op.Spec.TaskID == "" {
+			return errors.New("taskId is required by KillTask")
+		}
+	}
+	return nil
+}
+
+func finishDruidOperation(sdk client.Client, op *v1alpha1.DruidOperation, phase v1alpha1.DruidOperationPhase, msg, result string, emitEvents EventEmitter) error {
+	now := metav1.Now()
+	if err := druidObjectStatusPatcher(sdk, op, v1alpha1.DruidOperationStatus{
+		Phase:          phase,
+		StartTime:      op.Status.StartTime,
+		CompletionTime: &now,
+		Message:        msg,
+		Result:         result,
+		Step:           op.Status.Step,
+		Server:         op.Status.Server,
+	}); err != nil {
+		return err
+	}
+
+	if phase == v1alpha1.DruidOperationFailed {
+		emitEvents.EmitEventGeneric(op, "DruidOperationFailed", "", errors.New(msg))
+	} else {
+		emitEvents.EmitEventGeneric(op, "DruidOperationSucceeded", msg, nil)
+	}
+	return nil
+}
+
+func truncateDruidOperationResult(result string) string {
+	if len(result) > druidOperationMaxResultLength {
+		return result[:druidOperationMaxResultLength] + "..."
+	}
+	return result
+}
@@ -0,0 +1,22 @@
+package druid
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	druidv1alpha1 "github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+// DruidOperationReconciler reconciles a DruidOperation object
+type DruidOperationReconciler struct {
+	druidObjectReconciler[*druidv1alpha1.DruidOperation]
+}
+
+func NewDruidOperationReconciler(mgr ctrl.Manager) *DruidOperationReconciler {
+	return &DruidOperationReconciler{
+		druidObjectReconciler: newDruidObjectReconciler(mgr, "DruidOperation",
+			func() *druidv1alpha1.DruidOperation { return &druidv1alpha1.DruidOperation{} }, runDruidOperation),
+	}
+}
+
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidoperations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidoperations/status,verbs=get;update;patch
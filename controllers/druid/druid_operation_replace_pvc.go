@@ -0,0 +1,303 @@
+package druid
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Steps of the ReplacePVC action
+const (
+	replacePVCDecommissioning = "Decommissioning"
+	replacePVCDeleting        = "Deleting"
+	replacePVCLoading         = "Loading"
+)
+
+const (
+	defaultReplacePVCDecommissionTimeout = time.Hour
+	defaultReplacePVCLoadTimeout         = time.Hour
+)
+
+// replaceDruidPVC replaces the PVCs of one StatefulSet ordinal of a node spec.
+// Optionally the historical is decommissioned first, then the pod and its PVCs are deleted together so that the
+// StatefulSet recreates both fresh. Action is complete once the new pod is ready and the coordinator has no segment
+// left to load, and fails if the decommissioning or the loading takes longer than its timeout.
+// Failures are retried until the step times out, the server is then removed from decommissioningNodes before failing.
+func replaceDruidPVC(sdk client.Client, op *v1alpha1.DruidOperation, drd *v1alpha1.Druid, emitEvents EventEmitter) (bool, string, error) {
+	done, result, err := runReplacePVCStep(sdk, op, drd, emitEvents)
+	if err == nil {
+		return done, result, nil
+	}
+	if _, expired := getReplacePVCStepTimeout(op); !expired {
+		return false, "", &druidOperationRetryError{err: err}
+	}
+	if op.Status.Server != "" {
+		if err := setDecommissioningNode(sdk, drd, op.Status.Server, false); err != nil {
+			return false, "", &druidOperationRetryError{err: fmt.Errorf("failed to remove server [%s] from decommissioningNodes due to [%s]", op.Status.Server, err.Error())}
+		}
+	}
+	return false, "", err
+}
+
+// getReplacePVCStepTimeout returns the timeout of the current step of the ReplacePVC action and whether it expired.
+// The step finding the server to decommission is bounded by the decommission timeout from the start of the operation.
+func getReplacePVCStepTimeout(op *v1alpha1.DruidOperation) (time.Duration, bool) {
+	timeout, start := defaultReplacePVCLoadTimeout, op.Status.StepStartTime
+	if op.Spec.LoadTimeout != nil {
+		timeout = op.Spec.LoadTimeout.Duration
+	}
+	if op.Status.Step == "" || op.Status.Step == replacePVCDecommissioning {
+		timeout = defaultReplacePVCDecommissionTimeout
+		if op.Spec.DecommissionTimeout != nil {
+			timeout = op.Spec.DecommissionTimeout.Duration
+		}
+	}
+	if op.Status.Step == "" {
+		start = op.Status.StartTime
+	}
+	return timeout, start != nil && time.Since(start.Time) > timeout
+}
+
+func runReplacePVCStep(sdk client.Client, op *v1alpha1.DruidOperation, drd *v1alpha1.Druid, emitEvents EventEmitter) (bool, string, error) {
+	key := op.Spec.NodeSpecKey
+	nodeSpecUniqueStr := makeNodeSpecificUniqueString(drd, key)
+	podName := fmt.Sprintf("%s-%d", nodeSpecUniqueStr, *op.Spec.Ordinal)
+
+	pod := &v1.Pod{}
+	if err := sdk.Get(context.TODO(), *namespacedName(podName, drd.Namespace), pod); err != nil && !apierrors.IsNotFound(err) {
+		return false, "", err
+	} else if apierrors.IsNotFound(err) {
+		pod = nil
+	}
+
+	switch op.Status.Step {
+	case "":
+		if !op.Spec.Decommission || pod == nil || !isPodReady(pod) {
+			return false, "", setDruidOperationStep(sdk, op, replacePVCDeleting, "")
+		}
+
+		server, err := getDruidServerOfPod(sdk, drd, pod)
+		if err != nil {
+			return false, "", err
+		}
+		if err := setDecommissioningNode(sdk, drd, server, true); err != nil {
+			return false, "", err
+		}
+		return false, "", setDruidOperationStep(sdk, op, replacePVCDecommissioning, server)
+
+	case replacePVCDecommissioning:
+		if timeout, expired := getReplacePVCStepTimeout(op); expired {
+			return false, "", fmt.Errorf("segments not moved off server [%s] after %s", op.Status.Server, timeout)
+		}
+		c, err := newDruidAPIClient(sdk, drd, coordinator)
+		if err != nil {
+			return false, "", err
+		}
+		var servers []struct {
+			Host     string `json:"host"`
+			CurrSize int64  `json:"currSize"`
+		}
+		if _, err := c.do(http.MethodGet, "/druid/coordinator/v1/servers?simple", nil, &servers); err != nil {
+			return false, "", err
+		}
+		for _, s := range servers {
+			if s.Host == op.Status.Server && s.CurrSize > 0 {
+				// segments are still being moved off the server
+				return false, "", nil
+			}
+		}
+		return false, "", setDruidOperationStep(sdk, op, replacePVCDeleting, op.Status.Server)
+
+	case replacePVCDeleting:
+		deleted, err := deletePodPVCs(sdk, drd, nodeSpecUniqueStr, podName, emitEvents)
+		if err != nil {
+			return false, "", err
+		}
+
+		// pvcs are protected until the pod using them is deleted
+		if pod != nil {
+			if err := writers.Delete(context.TODO(), sdk, drd, pod, emitEvents); err != nil && !apierrors.IsNotFound(err) {
+				return false, "", err
+			}
+		}
+
+		msg := fmt.Sprintf("Deleted pod [%s] and pvcs %v", podName, deleted)
+		logger.Info(msg, "name", drd.Name, "namespace", drd.Namespace)
+		emitEvents.EmitEventGeneric(op, "DruidOperationPvcDeleted", msg, nil)
+		return false, "", setDruidOperationStep(sdk, op, replacePVCLoading, op.Status.Server)
+
+	case replacePVCLoading:
+		if timeout, expired := getReplacePVCStepTimeout(op); expired {
+			return false, "", fmt.Errorf("pod [%s] not ready or segments not loaded after %s", podName, timeout)
+		}
+		if pod == nil || pod.DeletionTimestamp != nil {
+			return false, "", nil
+		}
+
+		// the pod recreated before its pvcs were removed uses them again, keeping them terminating
+		terminating, err := hasTerminatingPodPVCs(sdk, drd, nodeSpecUniqueStr, podName, emitEvents)
+		if err != nil {
+			return false, "", err
+		}
+		if terminating {
+			if err := writers.Delete(context.TODO(), sdk, drd, pod, emitEvents); err != nil && !apierrors.IsNotFound(err) {
+				return false, "", err
+			}
+			return false, "", nil
+		}
+		if !isPodReady(pod) {
+			return false, "", nil
+		}
+
+		c, err := newDruidAPIClient(sdk, drd, coordinator)
+		if err != nil {
+			return false, "", err
+		}
+		loadStatus := map[string]int64{}
+		if _, err := c.do(http.MethodGet, "/druid/coordinator/v1/loadstatus?simple", nil, &loadStatus); err != nil {
+			return false, "", err
+		}
+		for _, left := range loadStatus {
+			if left > 0 {
+				return false, "", nil
+			}
+		}
+
+		if op.Status.Server != "" {
+			if err := setDecommissioningNode(sdk, drd, op.Status.Server, false); err != nil {
+				return false, "", err
+			}
+		}
+		return true, fmt.Sprintf("pvcs of pod [%s] replaced and segments loaded", podName), nil
+	}
+
+	return false, "", fmt.Errorf("unknown step [%s]", op.Status.Step)
+}
+
+// listPodPVCs returns the pvcs of the pod, created from volume claim templates and named <template>-<pod>.
+func listPodPVCs(sdk client.Client, drd *v1alpha1.Druid, nodeSpecUniqueStr, podName string, emitEvents EventEmitter) ([]object, error) {
+	pvcList, err := listPersistentVolumeClaims(sdk, drd, makeLabelsForPVCOfNodeSpec(drd, nodeSpecUniqueStr), emitEvents)
+	if err != nil {
+		return nil, err
+	}
+	pvcs := []object{}
+	for _, pvc := range pvcList {
+		if strings.HasSuffix(pvc.GetName(), "-"+podName) {
+			pvcs = append(pvcs, pvc)
+		}
+	}
+	return pvcs, nil
+}
+
+// deletePodPVCs deletes the pvcs of the pod, returns their names.
+func deletePodPVCs(sdk client.Client, drd *v1alpha1.Druid, nodeSpecUniqueStr, podName string, emitEvents EventEmitter) ([]string, error) {
+	pvcs, err := listPodPVCs(sdk, drd, nodeSpecUniqueStr, podName, emitEvents)
+	if err != nil {
+		return nil, err
+	}
+	deleted := []string{}
+	for _, pvc := range pvcs {
+		if err := writers.Delete(context.TODO(), sdk, drd, pvc, emitEvents); err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		deleted = append(deleted, pvc.GetName())
+	}
+	return deleted, nil
+}
+
+func hasTerminatingPodPVCs(sdk client.Client, drd *v1alpha1.Druid, nodeSpecUniqueStr, podName string, emitEvents EventEmitter) (bool, error) {
+	pvcs, err := listPodPVCs(sdk, drd, nodeSpecUniqueStr, podName, emitEvents)
+	if err != nil {
+		return false, err
+	}
+	for _, pvc := range pvcs {
+		if pvc.GetDeletionTimestamp() != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getDruidServerOfPod returns the server of the pod as known by the coordinator, the one to add to
+// decommissioningNodes.
+func getDruidServerOfPod(sdk client.Client, drd *v1alpha1.Druid, pod *v1.Pod) (string, error) {
+	c, err := newDruidAPIClient(sdk, drd, coordinator)
+	if err != nil {
+		return "", err
+	}
+	var servers []struct {
+		Host string `json:"host"`
+	}
+	if _, err := c.do(http.MethodGet, "/druid/coordinator/v1/servers?simple", nil, &servers); err != nil {
+		return "", err
+	}
+	for _, s := range servers {
+		if findPodByHost(s.Host, map[string]*v1.Pod{pod.Name: pod}) != nil {
+			return s.Host, nil
+		}
+	}
+	return "", fmt.Errorf("pod [%s] not found in the servers of the coordinator", pod.Name)
+}
+
+// makeLabelsForPVCOfNodeSpec returns the labels selecting the pvcs of one node spec.
+func makeLabelsForPVCOfNodeSpec(drd *v1alpha1.Druid, nodeSpecUniqueStr string) map[string]string {
+	labels := makeLabelsForPVC(drd)
+	labels["nodeSpecUniqueStr"] = nodeSpecUniqueStr
+	return labels
+}
+
+// setDecommissioningNode adds or removes the server from decommissioningNodes of the coordinator dynamic config.
+func setDecommissioningNode(sdk client.Client, drd *v1alpha1.Druid, server string, decommission bool) error {
+	c, err := newDruidAPIClient(sdk, drd, coordinator)
+	if err != nil {
+		return err
+	}
+
+	config := map[string]interface{}{}
+	if _, err := c.do(http.MethodGet, "/druid/coordinator/v1/config", nil, &config); err != nil {
+		return err
+	}
+
+	nodes := []string{}
+	if current, ok := config["decommissioningNodes"].([]interface{}); ok {
+		for _, n := range current {
+			if s, ok := n.(string); ok && s != server {
+				nodes = append(nodes, s)
+			}
+		}
+	}
+	if decommission {
+		nodes = append(nodes, server)
+	}
+	config["decommissioningNodes"] = nodes
+
+	_, err = c.do(http.MethodPost, "/druid/coordinator/v1/config", config, nil)
+	return err
+}
+
+func setDruidOperationStep(sdk client.Client, op *v1alpha1.DruidOperation, step, server string) error {
+	status := *op.Status.DeepCopy()
+	status.Step = step
+	status.Server = server
+	now := metav1.Now()
+	status.StepStartTime = &now
+	status.Message = fmt.Sprintf("%s: %s", op.Spec.Action, step)
+	return druidObjectStatusPatcher(sdk, op, status)
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package druid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makeTestDruidOperation(name, clusterRef string, created time.Time, phase v1alpha1.DruidOperationPhase) v1alpha1.DruidOperation {
+	return v1alpha1.DruidOperation{
+		ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Spec:       v1alpha1.DruidOperationSpec{ClusterRef: clusterRef, Action: v1alpha1.DruidOperationTriggerCompaction},
+		Status:     v1alpha1.DruidOperationStatus{Phase: phase},
+	}
+}
+
+func TestIsDruidOperationRunnable(t *testing.T) {
+	now := time.Now()
+	op := makeTestDruidOperation("op", "cluster", now, "")
+
+	cases := []struct {
+		name     string
+		ops      []v1alpha1.DruidOperation
+		runnable bool
+	}{
+		{"alone", []v1alpha1.DruidOperation{op}, true},
+		{"running on same cluster", []v1alpha1.DruidOperation{op, makeTestDruidOperation("other", "cluster", now.Add(time.Minute), v1alpha1.DruidOperationRunning)}, false},
+		{"running on other cluster", []v1alpha1.DruidOperation{op, makeTestDruidOperation("other", "another", now, v1alpha1.DruidOperationRunning)}, true},
+		{"older pending", []v1alpha1.DruidOperation{op, makeTestDruidOperation("other", "cluster", now.Add(-time.Minute), v1alpha1.DruidOperationPending)}, false},
+		{"newer pending", []v1alpha1.DruidOperation{op, makeTestDruidOperation("other", "cluster", now.Add(time.Minute), v1alpha1.DruidOperationPending)}, true},
+		{"older finished", []v1alpha1.DruidOperation{op, makeTestDruidOperation("other", "cluster", now.Add(-time.Minute), v1alpha1.DruidOperationSucceeded)}, true},
+	}
+
+	for _, c := range cases {
+		if actual := isDruidOperationRunnable(&op, c.ops); actual != c.runnable {
+			t.Errorf("%s: expected runnable [%v], got [%v]", c.name, c.runnable, actual)
+		}
+	}
+}
+
+func TestVerifyDruidOperationSpec(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+
+	op := &v1alpha1.DruidOperation{Spec: v1alpha1.DruidOperationSpec{Action: v1alpha1.DruidOperationRestartNode, NodeSpecKey: "brokers"}}
+	if err := verifyDruidOperationSpec(op, clusterSpec); err != nil {
+		t.Errorf("unexpected error [%s]", err)
+	}
+
+	op.Spec.NodeSpecKey = "unknown"
+	if err := verifyDruidOperationSpec(op, clusterSpec); err == nil {
+		t.Errorf("unknown node spec key must be rejected")
+	}
+
+	op.Spec = v1alpha1.DruidOperationSpec{Action: v1alpha1.DruidOperationKillTask}
+	if err := verifyDruidOperationSpec(op, clusterSpec); err == nil {
+		t.Errorf("KillTask without taskId must be rejected")
+	}
+
+	ordinal := int32(0)
+	op.Spec = v1alpha1.DruidOperationSpec{Action: v1alpha1.DruidOperationReplacePVC, NodeSpecKey: "historicals", Ordinal: &ordinal}
+	if err := verifyDruidOperationSpec(op, clusterSpec); err != nil {
+		t.Errorf("unexpected error [%s]", err)
+	}
+
+	ordinal = clusterSpec.Spec.Nodes["historicals"].Replicas
+	if err := verifyDruidOperationSpec(op, clusterSpec); err == nil {
+		t.Errorf("ordinal above replicas must be rejected")
+	}
+}
+
+func TestNewDruidAPIClient(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+
+	c, err := newDruidAPIClient(nil, clusterSpec, broker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "http://druid-druid-test-brokers.test-namespace.svc:8080"
+	if c.baseURL != expected {
+		t.Errorf("expected base url [%s], got [%s]", expected, c.baseURL)
+	}
+
+	if _, err := newDruidAPIClient(nil, clusterSpec, indexer); err == nil {
+		t.Errorf("missing node type must be rejected")
+	}
+
+	clusterSpec.Spec.Services = clusterSpec.Spec.Nodes["brokers"].Services
+	for key, nodeSpec := range clusterSpec.Spec.Nodes {
+		if nodeSpec.NodeType == overlord {
+			delete(clusterSpec.Spec.Nodes, key)
+		}
+	}
+	c, err = newDruidAPIClient(nil, clusterSpec, overlord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.baseURL != "http://druid-druid-test-coordinators.test-namespace.svc:8080" {
+		t.Errorf("overlord APIs must fall back to the coordinator, got [%s]", c.baseURL)
+	}
+}
+
+func TestDruidAPIClientDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("bad request"))
+			return
+		}
+		w.Write([]byte(`{"task":"abc"}`))
+	}))
+	defer server.Close()
+
+	c := &druidAPIClient{baseURL: server.URL, httpClient: server.Client()}
+
+	out := map[string]string{}
+	if _, err := c.do(http.MethodPost, "/ok", nil, &out); err != nil || out["task"] != "abc" {
+		t.Errorf("unexpected response [%v] [%v]", out, err)
+	}
+
+	if _, err := c.do(http.MethodPost, "/fail", `{}`, nil); err == nil || isDruidAPINotFound(err) {
+		t.Errorf("non 2xx response must return an error")
+	}
+}
+
+func TestGetReplacePVCStepTimeout(t *testing.T) {
+	started := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	op := &v1alpha1.DruidOperation{
+		Spec:   v1alpha1.DruidOperationSpec{DecommissionTimeout: &metav1.Duration{Duration: 3 * time.Hour}},
+		Status: v1alpha1.DruidOperationStatus{StartTime: &started, StepStartTime: &started, Step: replacePVCDecommissioning},
+	}
+	if timeout, expired := getReplacePVCStepTimeout(op); timeout != 3*time.Hour || expired {
+		t.Errorf("decommissioning must be bounded by the decommission timeout, got [%s] [%t]", timeout, expired)
+	}
+
+	op.Status.Step = replacePVCLoading
+	if timeout, expired := getReplacePVCStepTimeout(op); timeout != defaultReplacePVCLoadTimeout || !expired {
+		t.Errorf("loading must be bounded by the default load timeout, got [%s] [%t]", timeout, expired)
+	}
+
+	op.Status.Step = ""
+	op.Status.StepStartTime = nil
+	op.Spec.DecommissionTimeout = nil
+	if _, expired := getReplacePVCStepTimeout(op); !expired {
+		t.Errorf("step before decommissioning must be bounded from the start of the operation")
+	}
+}
@@ -0,0 +1,22 @@
+package druid
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	druidv1alpha1 "github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+// DruidRoleReconciler reconciles a DruidRole object
+type DruidRoleReconciler struct {
+	druidObjectReconciler[*druidv1alpha1.DruidRole]
+}
+
+func NewDruidRoleReconciler(mgr ctrl.Manager) *DruidRoleReconciler {
+	return &DruidRoleReconciler{
+		druidObjectReconciler: newDruidObjectReconciler(mgr, "DruidRole",
+			func() *druidv1alpha1.DruidRole { return &druidv1alpha1.DruidRole{} }, unfinishedSync(syncDruidRole)),
+	}
+}
+
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidroles/status,verbs=get;update;patch
@@ -0,0 +1,195 @@
+package druid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ruleFinalizerName      = "rule.finalizers.druid.apache.org"
+	defaultRulesDataSource = "_default"
+	defaultHistoricalTier  = "_default_tier"
+)
+
+// syncDruidRule applies the rules of the DruidRule to the coordinator when they differ from the coordinator rules.
+// A difference after a successful sync is reported as drift, then the rules are applied again.
+// Rules of the datasource are reset on deletion of the DruidRule.
+func syncDruidRule(sdk client.Client, rule *v1alpha1.DruidRule, emitEvents EventEmitter) error {
+	drd := &v1alpha1.Druid{}
+	if err := sdk.Get(context.TODO(), *namespacedName(rule.Spec.ClusterRef, rule.Namespace), drd); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if rule.DeletionTimestamp != nil {
+			// cluster is gone, nothing left to reset
+			return removeObjectFinalizer(sdk, rule, ruleFinalizerName)
+		}
+		return setDruidRuleNotSynced(sdk, rule, fmt.Sprintf("Druid CR [%s] not found", rule.Spec.ClusterRef), emitEvents)
+	}
+
+	c, err := newDruidAPIClient(sdk, drd, coordinator)
+	if err != nil {
+		if rule.DeletionTimestamp != nil {
+			return removeObjectFinalizer(sdk, rule, ruleFinalizerName)
+		}
+		return setDruidRuleNotSynced(sdk, rule, err.Error(), emitEvents)
+	}
+
+	dataSource := firstNonEmptyStr(rule.Spec.DataSource, defaultRulesDataSource)
+	path := fmt.Sprintf("/druid/coordinator/v1/rules/%s", url.PathEscape(dataSource))
+
+	if rule.DeletionTimestamp != nil {
+		if ContainsString(rule.Finalizers, ruleFinalizerName) {
+			if _, err := c.do(http.MethodPost, path, getResetDruidRules(dataSource), nil); err != nil {
+				return err
+			}
+			msg := fmt.Sprintf("Reset rules of datasource [%s]", dataSource)
+			logger.Info(msg, "name", rule.Name, "namespace", rule.Namespace)
+			emitEvents.EmitEventGeneric(rule, "DruidRuleReset", msg, nil)
+		}
+		return removeObjectFinalizer(sdk, rule, ruleFinalizerName)
+	}
+
+	if err := addObjectFinalizer(sdk, rule, ruleFinalizerName); err != nil {
+		return err
+	}
+
+	if err := verifyDruidRuleSpec(rule, drd); err != nil {
+		return setDruidRuleNotSynced(sdk, rule, err.Error(), emitEvents)
+	}
+
+	current := []v1alpha1.DruidRetentionRule{}
+	if _, err := c.do(http.MethodGet, path, nil, &current); err != nil {
+		return err
+	}
+
+	if areDruidRulesEqual(rule.Spec.Rules, current) {
+		if rule.Status.Synced {
+			return nil
+		}
+		status := *rule.Status.DeepCopy()
+		status.Synced = true
+		status.Message = "Coordinator rules match the spec"
+		return druidObjectStatusPatcher(sdk, rule, status)
+	}
+
+	status := *rule.Status.DeepCopy()
+	if rule.Status.Synced {
+		now := metav1.Now()
+		status.LastDriftTime = &now
+		e := fmt.Errorf("Coordinator rules of datasource [%s] drifted from DruidRule [%s], applying them again", dataSource, rule.Name)
+		emitEvents.EmitEventGeneric(rule, "DruidRuleDrift", "", e)
+	}
+
+	if _, err := c.do(http.MethodPost, path, rule.Spec.Rules, nil); err != nil {
+		return setDruidRuleNotSynced(sdk, rule, err.Error(), emitEvents)
+	}
+
+	now := metav1.Now()
+	status.Synced = true
+	status.LastSyncTime = &now
+	status.Message = "Rules applied to the coordinator"
+	if err := druidObjectStatusPatcher(sdk, rule, status); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Applied rules of DruidRule [%s] to the coordinator", rule.Name)
+	logger.Info(msg, "name", rule.Name, "namespace", rule.Namespace)
+	emitEvents.EmitEventGeneric(rule, "DruidRuleSynced", msg, nil)
+	return nil
+}
+
+// areDruidRulesEqual returns true if the coordinator rules are exactly the desired rules, once the defaults of the
+// coordinator are set. Fields of the coordinator rules not supported by the spec are ignored.
+func areDruidRulesEqual(desired, current []v1alpha1.DruidRetentionRule) bool {
+	if len(desired) != len(current) {
+		return false
+	}
+	for i := range desired {
+		if !reflect.DeepEqual(normalizeDruidRule(desired[i]), normalizeDruidRule(current[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeDruidRule(rule v1alpha1.DruidRetentionRule) v1alpha1.DruidRetentionRule {
+	rule = *rule.DeepCopy()
+	if rule.Period != "" && rule.IncludeFuture == nil {
+		includeFuture := true
+		rule.IncludeFuture = &includeFuture
+	}
+	if len(rule.TieredReplicants) == 0 {
+		rule.TieredReplicants = nil
+	}
+	return rule
+}
+
+// getResetDruidRules returns the rules of a datasource without DruidRule, the rules of the cluster default being the
+// ones of a new coordinator.
+func getResetDruidRules(dataSource string) []v1alpha1.DruidRetentionRule {
+	if dataSource != defaultRulesDataSource {
+		return []v1alpha1.DruidRetentionRule{}
+	}
+	return []v1alpha1.DruidRetentionRule{
+		{Type: "loadForever", TieredReplicants: map[string]int32{defaultHistoricalTier: 2}},
+	}
+}
+
+func setDruidRuleNotSynced(sdk client.Client, rule *v1alpha1.DruidRule, msg string, emitEvents EventEmitter) error {
+	if !rule.Status.Synced && rule.Status.Message == msg {
+		return nil
+	}
+	emitEvents.EmitEventGeneric(rule, "DruidRuleSyncFail", "", errors.New(msg))
+
+	status := *rule.Status.DeepCopy()
+	status.Synced = false
+	status.Message = msg
+	return druidObjectStatusPatcher(sdk, rule, status)
+}
+
+// verifyDruidRuleSpec checks rule parameters and that tiers of the rules are historical tiers of the druid cluster.
+func verifyDruidRuleSpec(rule *v1alpha1.DruidRule, drd *v1alpha1.Druid) error {
+	tiers := getHistoricalTiers(drd)
+
+	for i, r := range rule.Spec.Rules {
+		if (r.Type == "loadByPeriod" || r.Type == "dropByPeriod") && r.Period == "" {
+			return fmt.Errorf("rule [%d] of type [%s] requires a period", i, r.Type)
+		}
+		for tier := range r.TieredReplicants {
+			if !ContainsString(tiers, tier) {
+				return fmt.Errorf("tier [%s] of rule [%d] is not a historical tier of Druid CR [%s], known tiers %v", tier, i, drd.Name, tiers)
+			}
+		}
+	}
+	return nil
+}
+
+// getHistoricalTiers returns the sorted tiers of the historical node specs, from druid.server.tier in runtime properties.
+func getHistoricalTiers(drd *v1alpha1.Druid) []string {
+	tiers := []string{}
+	for _, nodeSpec := range getNodeSpecs(drd) {
+		if nodeSpec.NodeType != historical {
+			continue
+		}
+		tier, ok := getPropertyValue(nodeSpec.RuntimeProperties, "druid.server.tier")
+		if !ok {
+			tier = defaultHistoricalTier
+		}
+		if !ContainsString(tiers, tier) {
+			tiers = append(tiers, tier)
+		}
+	}
+	sort.Strings(tiers)
+	return tiers
+}
@@ -0,0 +1,22 @@
+package druid
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	druidv1alpha1 "github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+// DruidRuleReconciler reconciles a DruidRule object
+type DruidRuleReconciler struct {
+	druidObjectReconciler[*druidv1alpha1.DruidRule]
+}
+
+func NewDruidRuleReconciler(mgr ctrl.Manager) *DruidRuleReconciler {
+	return &DruidRuleReconciler{
+		druidObjectReconciler: newDruidObjectReconciler(mgr, "DruidRule",
+			func() *druidv1alpha1.DruidRule { return &druidv1alpha1.DruidRule{} }, unfinishedSync(syncDruidRule)),
+	}
+}
+
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidrules/status,verbs=get;update;patch
@@ -0,0 +1,69 @@
+package druid
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+func TestVerifyDruidRuleSpec(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+
+	if tiers := getHistoricalTiers(clusterSpec); !areStringArraysEqual(tiers, []string{defaultHistoricalTier}) {
+		t.Errorf("unexpected tiers %v", tiers)
+	}
+
+	rule := &v1alpha1.DruidRule{
+		Spec: v1alpha1.DruidRuleSpec{
+			ClusterRef: clusterSpec.Name,
+			Rules: []v1alpha1.DruidRetentionRule{
+				{Type: "loadByPeriod", Period: "P1M", TieredReplicants: map[string]int32{defaultHistoricalTier: 2}},
+				{Type: "dropForever"},
+			},
+		},
+	}
+	if err := verifyDruidRuleSpec(rule, clusterSpec); err != nil {
+		t.Errorf("unexpected error [%s]", err)
+	}
+
+	rule.Spec.Rules[0].TieredReplicants = map[string]int32{"hot": 2}
+	if err := verifyDruidRuleSpec(rule, clusterSpec); err == nil {
+		t.Errorf("unknown tier must be rejected")
+	}
+
+	rule.Spec.Rules[0] = v1alpha1.DruidRetentionRule{Type: "dropByPeriod"}
+	if err := verifyDruidRuleSpec(rule, clusterSpec); err == nil {
+		t.Errorf("dropByPeriod without period must be rejected")
+	}
+}
+
+func TestAreDruidRulesEqual(t *testing.T) {
+	var current []v1alpha1.DruidRetentionRule
+	if err := json.Unmarshal([]byte(`[{"type":"loadByPeriod","period":"P1M","includeFuture":true,"useDefaultTierForNull":true,"tieredReplicants":{"_default_tier":2}},{"type":"dropForever"}]`), &current); err != nil {
+		t.Fatal(err)
+	}
+	desired := []v1alpha1.DruidRetentionRule{
+		{Type: "loadByPeriod", Period: "P1M", TieredReplicants: map[string]int32{defaultHistoricalTier: 2}},
+		{Type: "dropForever"},
+	}
+	if !areDruidRulesEqual(desired, current) {
+		t.Errorf("defaults of the coordinator must be ignored")
+	}
+
+	current[0].TieredReplicants["cold"] = 1
+	if areDruidRulesEqual(desired, current) {
+		t.Errorf("tier added on the coordinator must be detected")
+	}
+
+	includeFuture := false
+	current[0].TieredReplicants = map[string]int32{defaultHistoricalTier: 2}
+	current[0].IncludeFuture = &includeFuture
+	if areDruidRulesEqual(desired, current) {
+		t.Errorf("changed includeFuture must be detected")
+	}
+
+	if areDruidRulesEqual(desired, current[:1]) {
+		t.Errorf("removed rule must be detected")
+	}
+}
@@ -0,0 +1,380 @@
+package druid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	userFinalizerName = "user.finalizers.druid.apache.org"
+	roleFinalizerName = "role.finalizers.druid.apache.org"
+	// internal user of druid-basic-security, never pruned
+	druidSystemUser = "druid_system"
+)
+
+// druidSecurityPaths are the base paths of the basic security APIs of the authenticator and authorizer of a cluster.
+type druidSecurityPaths struct {
+	authentication string
+	authorization  string
+}
+
+func makeDruidSecurityPaths(drd *v1alpha1.Druid) druidSecurityPaths {
+	return druidSecurityPaths{
+		authentication: fmt.Sprintf("/druid-ext/basic-security/authentication/db/%s",
+			url.PathEscape(firstNonEmptyStr(drd.Spec.Auth.AuthenticatorName, defaultAuthenticatorName))),
+		authorization: fmt.Sprintf("/druid-ext/basic-security/authorization/db/%s",
+			url.PathEscape(firstNonEmptyStr(drd.Spec.Auth.AuthorizerName, defaultAuthorizerName))),
+	}
+}
+
+// syncDruidUser creates the user in the authenticator and authorizer of the cluster, sets its password when the
+// Secret changes, assigns the roles of the spec and prunes undeclared users if requested.
+// User is deleted on deletion of the DruidUser.
+func syncDruidUser(sdk client.Client, user *v1alpha1.DruidUser, emitEvents EventEmitter) error {
+	drd := &v1alpha1.Druid{}
+	if err := sdk.Get(context.TODO(), *namespacedName(user.Spec.ClusterRef, user.Namespace), drd); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if user.DeletionTimestamp != nil {
+			return removeObjectFinalizer(sdk, user, userFinalizerName)
+		}
+		return setDruidUserNotSynced(sdk, user, fmt.Sprintf("Druid CR [%s] not found", user.Spec.ClusterRef), emitEvents)
+	}
+
+	var c *druidAPIClient
+	err := fmt.Errorf("Druid CR [%s] has no spec.auth", user.Spec.ClusterRef)
+	if drd.Spec.Auth != nil {
+		c, err = newDruidAPIClient(sdk, drd, coordinator)
+	}
+	if err != nil {
+		if user.DeletionTimestamp != nil {
+			return removeObjectFinalizer(sdk, user, userFinalizerName)
+		}
+		return setDruidUserNotSynced(sdk, user, err.Error(), emitEvents)
+	}
+
+	paths := makeDruidSecurityPaths(drd)
+	name := firstNonEmptyStr(user.Spec.Name, user.Name)
+
+	if user.DeletionTimestamp != nil {
+		if err := deleteDruidUser(c, paths, name); err != nil {
+			return err
+		}
+		return removeObjectFinalizer(sdk, user, userFinalizerName)
+	}
+
+	if err := addObjectFinalizer(sdk, user, userFinalizerName); err != nil {
+		return err
+	}
+
+	status := *user.Status.DeepCopy()
+
+	created, err := ensureDruidSecurityEntity(c, fmt.Sprintf("%s/users/%s", paths.authentication, url.PathEscape(name)))
+	if err != nil {
+		return setDruidUserNotSynced(sdk, user, err.Error(), emitEvents)
+	}
+	if _, err := ensureDruidSecurityEntity(c, fmt.Sprintf("%s/users/%s", paths.authorization, url.PathEscape(name))); err != nil {
+		return setDruidUserNotSynced(sdk, user, err.Error(), emitEvents)
+	}
+	if created {
+		msg := fmt.Sprintf("Created user [%s]", name)
+		logger.Info(msg, "name", user.Name, "namespace", user.Namespace)
+		emitEvents.EmitEventGeneric(user, "DruidUserCreated", msg, nil)
+	}
+
+	if user.Spec.PasswordSecretRef != nil {
+		password, version, err := getSecretKey(sdk, user.Namespace, user.Spec.PasswordSecretRef)
+		if err != nil {
+			return setDruidUserNotSynced(sdk, user, err.Error(), emitEvents)
+		}
+		if updated, err := syncDruidUserPassword(c, paths, name, password, version, created, &status); err != nil {
+			return setDruidUserNotSynced(sdk, user, err.Error(), emitEvents)
+		} else if updated {
+			emitEvents.EmitEventGeneric(user, "DruidUserCredentialsUpdated", fmt.Sprintf("Updated credentials of user [%s]", name), nil)
+		}
+	}
+
+	if err := syncDruidUserRoles(c, paths, name, user.Spec.Roles); err != nil {
+		return setDruidUserNotSynced(sdk, user, err.Error(), emitEvents)
+	}
+
+	if user.Spec.Prune {
+		adminUser, _, err := getDruidAuthCredentials(sdk, drd)
+		if err != nil {
+			return setDruidUserNotSynced(sdk, user, err.Error(), emitEvents)
+		}
+		declared, err := getDeclaredDruidUsers(sdk, user)
+		if err != nil {
+			return err
+		}
+		declared[adminUser] = true
+		declared["admin"] = true
+		declared[druidSystemUser] = true
+
+		pruned, err := pruneDruidUsers(c, paths, declared)
+		if err != nil {
+			return setDruidUserNotSynced(sdk, user, err.Error(), emitEvents)
+		}
+		for _, u := range pruned {
+			msg := fmt.Sprintf("Pruned undeclared user [%s]", u)
+			logger.Info(msg, "name", user.Name, "namespace", user.Namespace)
+			emitEvents.EmitEventGeneric(user, "DruidUserPruned", msg, nil)
+		}
+	}
+
+	status.Synced = true
+	status.Message = "User in sync with the coordinator"
+	if reflect.DeepEqual(status, user.Status) {
+		return nil
+	}
+	return druidObjectStatusPatcher(sdk, user, status)
+}
+
+// syncDruidRole creates the role in the authorizer of the cluster and sets its permissions when they change.
+// Role is deleted on deletion of the DruidRole.
+func syncDruidRole(sdk client.Client, role *v1alpha1.DruidRole, emitEvents EventEmitter) error {
+	drd := &v1alpha1.Druid{}
+	if err := sdk.Get(context.TODO(), *namespacedName(role.Spec.ClusterRef, role.Namespace), drd); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if role.DeletionTimestamp != nil {
+			return removeObjectFinalizer(sdk, role, roleFinalizerName)
+		}
+		return setDruidRoleNotSynced(sdk, role, fmt.Sprintf("Druid CR [%s] not found", role.Spec.ClusterRef), emitEvents)
+	}
+
+	var c *druidAPIClient
+	err := fmt.Errorf("Druid CR [%s] has no spec.auth", role.Spec.ClusterRef)
+	if drd.Spec.Auth != nil {
+		c, err = newDruidAPIClient(sdk, drd, coordinator)
+	}
+	if err != nil {
+		if role.DeletionTimestamp != nil {
+			return removeObjectFinalizer(sdk, role, roleFinalizerName)
+		}
+		return setDruidRoleNotSynced(sdk, role, err.Error(), emitEvents)
+	}
+
+	path := fmt.Sprintf("%s/roles/%s", makeDruidSecurityPaths(drd).authorization, url.PathEscape(firstNonEmptyStr(role.Spec.Name, role.Name)))
+
+	if role.DeletionTimestamp != nil {
+		if _, err := c.do(http.MethodDelete, path, nil, nil); err != nil && !isDruidAPINotFound(err) {
+			return err
+		}
+		return removeObjectFinalizer(sdk, role, roleFinalizerName)
+	}
+
+	if err := addObjectFinalizer(sdk, role, roleFinalizerName); err != nil {
+		return err
+	}
+
+	status := *role.Status.DeepCopy()
+
+	created, err := ensureDruidSecurityEntity(c, path)
+	if err != nil {
+		return setDruidRoleNotSynced(sdk, role, err.Error(), emitEvents)
+	}
+
+	if updated, err := syncDruidRolePermissions(c, path, role.Spec.Permissions, created, &status); err != nil {
+		return setDruidRoleNotSynced(sdk, role, err.Error(), emitEvents)
+	} else if updated {
+		msg := fmt.Sprintf("Set permissions of role [%s]", firstNonEmptyStr(role.Spec.Name, role.Name))
+		logger.Info(msg, "name", role.Name, "namespace", role.Namespace)
+		emitEvents.EmitEventGeneric(role, "DruidRolePermissionsUpdated", msg, nil)
+	}
+
+	status.Synced = true
+	status.Message = "Role in sync with the coordinator"
+	if reflect.DeepEqual(status, role.Status) {
+		return nil
+	}
+	return druidObjectStatusPatcher(sdk, role, status)
+}
+
+// syncDruidUserPassword sets the password of the user if it was just created or the password Secret changed since
+// the last update, returns true if set. The version of the Secret is recorded instead of the password, so that
+// nothing derived from it is stored in the status.
+func syncDruidUserPassword(c *druidAPIClient, paths druidSecurityPaths, name, password, version string, created bool, status *v1alpha1.DruidUserStatus) (bool, error) {
+	if !created && version == status.PasswordSecretVersion {
+		return false, nil
+	}
+	if _, err := c.do(http.MethodPost, fmt.Sprintf("%s/users/%s/credentials", paths.authentication, url.PathEscape(name)),
+		map[string]string{"password": password}, nil); err != nil {
+		return false, err
+	}
+	status.PasswordSecretVersion = version
+	return true, nil
+}
+
+// syncDruidRolePermissions sets the permissions of the role if it was just created or they changed since the last
+// update, returns true if set.
+func syncDruidRolePermissions(c *druidAPIClient, path string, permissions []v1alpha1.DruidPermission, created bool, status *v1alpha1.DruidRoleStatus) (bool, error) {
+	if permissions == nil {
+		permissions = []v1alpha1.DruidPermission{}
+	}
+	hash, err := getJSONHash(permissions)
+	if err != nil {
+		return false, err
+	}
+	if !created && hash == status.PermissionsHash {
+		return false, nil
+	}
+	if _, err := c.do(http.MethodPost, path+"/permissions", permissions, nil); err != nil {
+		return false, err
+	}
+	status.PermissionsHash = hash
+	return true, nil
+}
+
+// ensureDruidSecurityEntity creates the user or role at path if it does not exist, returns true if created.
+func ensureDruidSecurityEntity(c *druidAPIClient, path string) (bool, error) {
+	_, err := c.do(http.MethodGet, path, nil, nil)
+	if err == nil {
+		return false, nil
+	}
+	if !isDruidAPINotFound(err) {
+		return false, err
+	}
+	if _, err := c.do(http.MethodPost, path, nil, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// syncDruidUserRoles assigns the given roles to the user and unassigns the other ones.
+func syncDruidUserRoles(c *druidAPIClient, paths druidSecurityPaths, name string, roles []string) error {
+	userPath := fmt.Sprintf("%s/users/%s", paths.authorization, url.PathEscape(name))
+
+	current := struct {
+		Roles []string `json:"roles"`
+	}{}
+	if _, err := c.do(http.MethodGet, userPath, nil, &current); err != nil {
+		return err
+	}
+
+	toAssign, toUnassign := diffDruidUserRoles(roles, current.Roles)
+	for _, r := range toAssign {
+		if _, err := c.do(http.MethodPost, fmt.Sprintf("%s/roles/%s", userPath, url.PathEscape(r)), nil, nil); err != nil {
+			return err
+		}
+	}
+	for _, r := range toUnassign {
+		if _, err := c.do(http.MethodDelete, fmt.Sprintf("%s/roles/%s", userPath, url.PathEscape(r)), nil, nil); err != nil && !isDruidAPINotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffDruidUserRoles returns the desired roles not assigned yet and the assigned roles not desired anymore.
+func diffDruidUserRoles(desired, current []string) ([]string, []string) {
+	toAssign := []string{}
+	for _, r := range desired {
+		if !ContainsString(current, r) && !ContainsString(toAssign, r) {
+			toAssign = append(toAssign, r)
+		}
+	}
+	toUnassign := []string{}
+	for _, r := range current {
+		if !ContainsString(desired, r) {
+			toUnassign = append(toUnassign, r)
+		}
+	}
+	return toAssign, toUnassign
+}
+
+// pruneDruidUsers deletes the users of the authenticator and authorizer which are not declared, returns the pruned users.
+func pruneDruidUsers(c *druidAPIClient, paths druidSecurityPaths, declared map[string]bool) ([]string, error) {
+	pruned := []string{}
+	for _, base := range []string{paths.authentication, paths.authorization} {
+		users := []string{}
+		if _, err := c.do(http.MethodGet, base+"/users", nil, &users); err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			if declared[u] || ContainsString(pruned, u) {
+				continue
+			}
+			if err := deleteDruidUser(c, paths, u); err != nil {
+				return nil, err
+			}
+			pruned = append(pruned, u)
+		}
+	}
+	return pruned, nil
+}
+
+func deleteDruidUser(c *druidAPIClient, paths druidSecurityPaths, name string) error {
+	for _, base := range []string{paths.authentication, paths.authorization} {
+		if _, err := c.do(http.MethodDelete, fmt.Sprintf("%s/users/%s", base, url.PathEscape(name)), nil, nil); err != nil && !isDruidAPINotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDeclaredDruidUsers returns the user names declared by all the DruidUsers of the cluster.
+func getDeclaredDruidUsers(sdk client.Client, user *v1alpha1.DruidUser) (map[string]bool, error) {
+	list := &v1alpha1.DruidUserList{}
+	if err := sdk.List(context.TODO(), list, client.InNamespace(user.Namespace)); err != nil {
+		return nil, err
+	}
+
+	declared := map[string]bool{}
+	for _, item := range list.Items {
+		if item.Spec.ClusterRef != user.Spec.ClusterRef || item.DeletionTimestamp != nil {
+			continue
+		}
+		declared[firstNonEmptyStr(item.Spec.Name, item.Name)] = true
+	}
+	return declared, nil
+}
+
+// getSecretKey returns the value of the key of the Secret, and the version of the value as the uid and
+// resourceVersion of the Secret with the key.
+func getSecretKey(sdk client.Client, namespace string, selector *v1.SecretKeySelector) (string, string, error) {
+	secret := &v1.Secret{}
+	if err := sdk.Get(context.TODO(), *namespacedName(selector.Name, namespace), secret); err != nil {
+		return "", "", fmt.Errorf("failed to get Secret [%s] due to [%s]", selector.Name, err.Error())
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", "", fmt.Errorf("Secret [%s] has no key [%s]", selector.Name, selector.Key)
+	}
+	return string(value), fmt.Sprintf("%s/%s/%s", secret.UID, secret.ResourceVersion, selector.Key), nil
+}
+
+func setDruidUserNotSynced(sdk client.Client, user *v1alpha1.DruidUser, msg string, emitEvents EventEmitter) error {
+	if !user.Status.Synced && user.Status.Message == msg {
+		return nil
+	}
+	emitEvents.EmitEventGeneric(user, "DruidUserSyncFail", "", errors.New(msg))
+
+	status := *user.Status.DeepCopy()
+	status.Synced = false
+	status.Message = msg
+	return druidObjectStatusPatcher(sdk, user, status)
+}
+
+func setDruidRoleNotSynced(sdk client.Client, role *v1alpha1.DruidRole, msg string, emitEvents EventEmitter) error {
+	if !role.Status.Synced && role.Status.Message == msg {
+		return nil
+	}
+	emitEvents.EmitEventGeneric(role, "DruidRoleSyncFail", "", errors.New(msg))
+
+	status := *role.Status.DeepCopy()
+	status.Synced = false
+	status.Message = msg
+	return druidObjectStatusPatcher(sdk, role, status)
+}
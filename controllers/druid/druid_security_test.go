@@ -0,0 +1,91 @@
+package druid
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+func TestDiffDruidUserRoles(t *testing.T) {
+	toAssign, toUnassign := diffDruidUserRoles([]string{"reader", "writer", "reader"}, []string{"writer", "admin"})
+	if !reflect.DeepEqual(toAssign, []string{"reader"}) {
+		t.Errorf("unexpected roles to assign %v", toAssign)
+	}
+	if !reflect.DeepEqual(toUnassign, []string{"admin"}) {
+		t.Errorf("unexpected roles to unassign %v", toUnassign)
+	}
+
+	toAssign, toUnassign = diffDruidUserRoles(nil, nil)
+	if len(toAssign) != 0 || len(toUnassign) != 0 {
+		t.Errorf("no roles must give no changes")
+	}
+}
+
+// newRecordingDruidAPIClient returns a client of a druid API recording the bodies of the POST requests by path.
+func newRecordingDruidAPIClient(t *testing.T) (*druidAPIClient, map[string][]string) {
+	posted := map[string][]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			posted[r.URL.Path] = append(posted[r.URL.Path], string(body))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return &druidAPIClient{baseURL: server.URL, httpClient: server.Client()}, posted
+}
+
+func TestSyncDruidUserPassword(t *testing.T) {
+	c, posted := newRecordingDruidAPIClient(t)
+	paths := druidSecurityPaths{authentication: "/authentication", authorization: "/authorization"}
+	path := "/authentication/users/analyst/credentials"
+	status := &v1alpha1.DruidUserStatus{}
+
+	if updated, err := syncDruidUserPassword(c, paths, "analyst", "secret", "uid/1/password", false, status); err != nil || !updated {
+		t.Fatalf("password must be set on first sync, got [%t] [%v]", updated, err)
+	}
+	if status.PasswordSecretVersion != "uid/1/password" || !reflect.DeepEqual(posted[path], []string{`{"password":"secret"}`}) {
+		t.Errorf("unexpected status [%+v] and requests %v", status, posted)
+	}
+
+	if updated, _ := syncDruidUserPassword(c, paths, "analyst", "secret", "uid/1/password", false, status); updated || len(posted[path]) != 1 {
+		t.Errorf("password must not be set again for the same Secret version")
+	}
+	if updated, _ := syncDruidUserPassword(c, paths, "analyst", "secret", "uid/1/password", true, status); !updated || len(posted[path]) != 2 {
+		t.Errorf("password must be set for a created user")
+	}
+	if updated, _ := syncDruidUserPassword(c, paths, "analyst", "changed", "uid/2/password", false, status); !updated || posted[path][2] != `{"password":"changed"}` {
+		t.Errorf("password must be set when the Secret changes, got %v", posted[path])
+	}
+	if strings.Contains(fmt.Sprintf("%+v", status), "changed") {
+		t.Errorf("status must not hold the password [%+v]", status)
+	}
+}
+
+func TestSyncDruidRolePermissions(t *testing.T) {
+	c, posted := newRecordingDruidAPIClient(t)
+	path := "/authorization/roles/reader"
+	status := &v1alpha1.DruidRoleStatus{}
+	permissions := []v1alpha1.DruidPermission{{Resource: v1alpha1.DruidResource{Type: "DATASOURCE", Name: "wikipedia"}, Action: "READ"}}
+
+	if updated, err := syncDruidRolePermissions(c, path, permissions, false, status); err != nil || !updated || status.PermissionsHash == "" {
+		t.Fatalf("permissions must be set on first sync, got [%t] [%v] [%+v]", updated, err, status)
+	}
+	if updated, _ := syncDruidRolePermissions(c, path, permissions, false, status); updated || len(posted[path+"/permissions"]) != 1 {
+		t.Errorf("unchanged permissions must not be set again")
+	}
+
+	permissions[0].Action = "WRITE"
+	if updated, _ := syncDruidRolePermissions(c, path, permissions, false, status); !updated || len(posted[path+"/permissions"]) != 2 {
+		t.Errorf("changed permissions must be set")
+	}
+
+	if updated, _ := syncDruidRolePermissions(c, path, nil, false, status); !updated || posted[path+"/permissions"][2] != "[]" {
+		t.Errorf("no permissions must clear the permissions of the role, got %v", posted[path+"/permissions"])
+	}
+}
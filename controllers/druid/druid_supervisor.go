@@ -0,0 +1,202 @@
+package druid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const supervisorFinalizerName = "supervisor.finalizers.druid.apache.org"
+
+// supervisorStatusPayload is the payload of /druid/indexer/v1/supervisor/<id>/status
+type supervisorStatusPayload struct {
+	State         string `json:"state"`
+	DetailedState string `json:"detailedState"`
+	Healthy       bool   `json:"healthy"`
+	Suspended     bool   `json:"suspended"`
+	AggregateLag  int64  `json:"aggregateLag"`
+}
+
+// syncDruidSupervisor submits the supervisor spec to the overlord when its normalized hash changes,
+// suspends or resumes it as requested and mirrors the supervisor state in status.
+// Supervisor is terminated on deletion of the DruidSupervisor.
+func syncDruidSupervisor(sdk client.Client, sup *v1alpha1.DruidSupervisor, emitEvents EventEmitter) error {
+	drd := &v1alpha1.Druid{}
+	if err := sdk.Get(context.TODO(), *namespacedName(sup.Spec.ClusterRef, sup.Namespace), drd); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if sup.DeletionTimestamp != nil {
+			// cluster is gone, nothing left to terminate
+			return removeObjectFinalizer(sdk, sup, supervisorFinalizerName)
+		}
+		return setDruidSupervisorMessage(sdk, sup, fmt.Sprintf("Druid CR [%s] not found", sup.Spec.ClusterRef), emitEvents)
+	}
+
+	c, err := newDruidAPIClient(sdk, drd, overlord)
+	if err != nil {
+		if sup.DeletionTimestamp != nil {
+			return removeObjectFinalizer(sdk, sup, supervisorFinalizerName)
+		}
+		return setDruidSupervisorMessage(sdk, sup, err.Error(), emitEvents)
+	}
+
+	if sup.DeletionTimestamp != nil {
+		if ContainsString(sup.Finalizers, supervisorFinalizerName) {
+			if err := terminateSupervisor(c, sup.Status.SupervisorID); err != nil {
+				return err
+			}
+			msg := fmt.Sprintf("Terminated supervisor [%s]", sup.Status.SupervisorID)
+			logger.Info(msg, "name", sup.Name, "namespace", sup.Namespace)
+			emitEvents.EmitEventGeneric(sup, "DruidSupervisorTerminated", msg, nil)
+		}
+		return removeObjectFinalizer(sdk, sup, supervisorFinalizerName)
+	}
+
+	if err := addObjectFinalizer(sdk, sup, supervisorFinalizerName); err != nil {
+		return err
+	}
+
+	spec, id, err := parseSupervisorSpec(sup.Spec.SupervisorSpec)
+	if err != nil {
+		return setDruidSupervisorMessage(sdk, sup, err.Error(), emitEvents)
+	}
+	hash, err := getJSONHash(spec)
+	if err != nil {
+		return err
+	}
+
+	status := *sup.Status.DeepCopy()
+
+	// datasource changed, the supervisor of the previous datasource is not managed anymore
+	if status.SupervisorID != "" && status.SupervisorID != id {
+		if err := terminateSupervisor(c, status.SupervisorID); err != nil {
+			return err
+		}
+	}
+
+	payload, err := getSupervisorStatus(c, id)
+	if err != nil && !isDruidAPINotFound(err) {
+		return err
+	}
+
+	if isDruidAPINotFound(err) || hash != status.SpecHash || id != status.SupervisorID {
+		// submitted spec carries the suspended flag, so that an update does not resume a suspended supervisor
+		spec["suspended"] = sup.Spec.Suspended
+		if _, err := c.do(http.MethodPost, "/druid/indexer/v1/supervisor", spec, nil); err != nil {
+			return setDruidSupervisorMessage(sdk, sup, err.Error(), emitEvents)
+		}
+
+		now := metav1.Now()
+		status.SupervisorID = id
+		status.SpecHash = hash
+		status.LastUpdateTime = &now
+		status.Message = "Supervisor spec submitted to the overlord"
+
+		msg := fmt.Sprintf("Submitted spec of supervisor [%s]", id)
+		logger.Info(msg, "name", sup.Name, "namespace", sup.Namespace)
+		emitEvents.EmitEventGeneric(sup, "DruidSupervisorSubmitted", msg, nil)
+	} else {
+		if payload.Suspended != sup.Spec.Suspended {
+			action := "resume"
+			if sup.Spec.Suspended {
+				action = "suspend"
+			}
+			if _, err := c.do(http.MethodPost, fmt.Sprintf("/druid/indexer/v1/supervisor/%s/%s", url.PathEscape(id), action), nil, nil); err != nil {
+				return setDruidSupervisorMessage(sdk, sup, err.Error(), emitEvents)
+			}
+			emitEvents.EmitEventGeneric(sup, "DruidSupervisorSuspendedUpdate", fmt.Sprintf("Supervisor [%s] %s requested", id, action), nil)
+		}
+
+		status.State = payload.State
+		status.DetailedState = payload.DetailedState
+		status.Healthy = payload.Healthy
+		status.AggregateLag = payload.AggregateLag
+		status.Message = "Supervisor spec in sync with the overlord"
+	}
+
+	if reflect.DeepEqual(status, sup.Status) {
+		return nil
+	}
+	return druidObjectStatusPatcher(sdk, sup, status)
+}
+
+// parseSupervisorSpec returns the unmarshalled spec and the supervisor id, which is the datasource of the spec.
+func parseSupervisorSpec(supervisorSpec string) (map[string]interface{}, string, error) {
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(supervisorSpec), &spec); err != nil {
+		return nil, "", fmt.Errorf("invalid supervisorSpec json due to [%s]", err.Error())
+	}
+
+	// dataSchema is nested in spec since druid 0.16, top level before
+	for _, parent := range []interface{}{spec["spec"], spec} {
+		if p, ok := parent.(map[string]interface{}); ok {
+			if dataSchema, ok := p["dataSchema"].(map[string]interface{}); ok {
+				if dataSource, ok := dataSchema["dataSource"].(string); ok && dataSource != "" {
+					return spec, dataSource, nil
+				}
+			}
+		}
+	}
+	return nil, "", errors.New("supervisorSpec has no dataSchema.dataSource")
+}
+
+func getSupervisorStatus(c *druidAPIClient, id string) (*supervisorStatusPayload, error) {
+	resp := struct {
+		Payload supervisorStatusPayload `json:"payload"`
+	}{}
+	if _, err := c.do(http.MethodGet, fmt.Sprintf("/druid/indexer/v1/supervisor/%s/status", url.PathEscape(id)), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Payload, nil
+}
+
+func terminateSupervisor(c *druidAPIClient, id string) error {
+	if id == "" {
+		return nil
+	}
+	if _, err := c.do(http.MethodPost, fmt.Sprintf("/druid/indexer/v1/supervisor/%s/terminate", url.PathEscape(id)), nil, nil); err != nil && !isDruidAPINotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func setDruidSupervisorMessage(sdk client.Client, sup *v1alpha1.DruidSupervisor, msg string, emitEvents EventEmitter) error {
+	if sup.Status.Message == msg {
+		return nil
+	}
+	emitEvents.EmitEventGeneric(sup, "DruidSupervisorSyncFail", "", errors.New(msg))
+
+	status := *sup.Status.DeepCopy()
+	status.Message = msg
+	return druidObjectStatusPatcher(sdk, sup, status)
+}
+
+// addObjectFinalizer adds the finalizer to druid operator CRs other than Druid.
+func addObjectFinalizer(sdk client.Client, obj client.Object, finalizer string) error {
+	if ContainsString(obj.GetFinalizers(), finalizer) {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	obj.SetFinalizers(append(obj.GetFinalizers(), finalizer))
+	return sdk.Patch(context.TODO(), obj, patch)
+}
+
+// removeObjectFinalizer removes the finalizer from druid operator CRs other than Druid.
+func removeObjectFinalizer(sdk client.Client, obj client.Object, finalizer string) error {
+	if !ContainsString(obj.GetFinalizers(), finalizer) {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	obj.SetFinalizers(RemoveString(obj.GetFinalizers(), finalizer))
+	return sdk.Patch(context.TODO(), obj, patch)
+}
@@ -0,0 +1,22 @@
+package druid
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	druidv1alpha1 "github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+// DruidSupervisorReconciler reconciles a DruidSupervisor object
+type DruidSupervisorReconciler struct {
+	druidObjectReconciler[*druidv1alpha1.DruidSupervisor]
+}
+
+func NewDruidSupervisorReconciler(mgr ctrl.Manager) *DruidSupervisorReconciler {
+	return &DruidSupervisorReconciler{
+		druidObjectReconciler: newDruidObjectReconciler(mgr, "DruidSupervisor",
+			func() *druidv1alpha1.DruidSupervisor { return &druidv1alpha1.DruidSupervisor{} }, unfinishedSync(syncDruidSupervisor)),
+	}
+}
+
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidsupervisors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidsupervisors/status,verbs=get;update;patch
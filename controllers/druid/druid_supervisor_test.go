@@ -0,0 +1,30 @@
+package druid
+
+import (
+	"testing"
+)
+
+func TestParseSupervisorSpec(t *testing.T) {
+	nested := `{"type":"kafka","spec":{"dataSchema":{"dataSource":"wikipedia"},"ioConfig":{"topic":"wiki"}}}`
+	spec, id, err := parseSupervisorSpec(nested)
+	if err != nil || id != "wikipedia" {
+		t.Errorf("unexpected id [%s] [%v]", id, err)
+	}
+
+	reordered := `{"spec":{"ioConfig":{"topic":"wiki"},"dataSchema":{"dataSource":"wikipedia"}},"type":"kafka"}`
+	other, _, _ := parseSupervisorSpec(reordered)
+	h1, _ := getJSONHash(spec)
+	h2, _ := getJSONHash(other)
+	if h1 != h2 {
+		t.Errorf("hash of the normalized spec must not depend on field order")
+	}
+
+	legacy := `{"type":"kafka","dataSchema":{"dataSource":"legacy"}}`
+	if _, id, err := parseSupervisorSpec(legacy); err != nil || id != "legacy" {
+		t.Errorf("unexpected id [%s] [%v]", id, err)
+	}
+
+	if _, _, err := parseSupervisorSpec(`{"type":"kafka"}`); err == nil {
+		t.Errorf("spec without datasource must be rejected")
+	}
+}
@@ -0,0 +1,22 @@
+package druid
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	druidv1alpha1 "github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+// DruidUserReconciler reconciles a DruidUser object
+type DruidUserReconciler struct {
+	druidObjectReconciler[*druidv1alpha1.DruidUser]
+}
+
+func NewDruidUserReconciler(mgr ctrl.Manager) *DruidUserReconciler {
+	return &DruidUserReconciler{
+		druidObjectReconciler: newDruidObjectReconciler(mgr, "DruidUser",
+			func() *druidv1alpha1.DruidUser { return &druidv1alpha1.DruidUser{} }, unfinishedSync(syncDruidUser)),
+	}
+}
+
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidusers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=druid.apache.org,resources=druidusers/status,verbs=get;update;patch
@@ -0,0 +1,115 @@
+package druid
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dynamicConfig describes a dynamic config stored by druid in the metadata store.
+type dynamicConfig struct {
+	name        string
+	nodeType    string
+	path        string
+	config      string
+	statusField string
+	appliedHash string
+}
+
+// syncDruidDynamicConfigs applies the coordinator and overlord dynamic configs of the spec when they changed
+// since last applied or drifted from the ones stored by druid. Applied hashes are recorded in status.
+// Failures are reported as events, the configs are re-applied on next reconcile.
+func syncDruidDynamicConfigs(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEmitter) error {
+	configs := []dynamicConfig{
+		{
+			name:        "coordinatorDynamicConfig",
+			nodeType:    coordinator,
+			path:        "/druid/coordinator/v1/config",
+			config:      m.Spec.CoordinatorDynamicConfig,
+			statusField: "coordinatorDynamicConfigHash",
+			appliedHash: m.Status.CoordinatorDynamicConfigHash,
+		},
+		{
+			name:        "overlordDynamicConfig",
+			nodeType:    overlord,
+			path:        "/druid/indexer/v1/worker",
+			config:      m.Spec.OverlordDynamicConfig,
+			statusField: "overlordDynamicConfigHash",
+			appliedHash: m.Status.OverlordDynamicConfigHash,
+		},
+	}
+
+	fields := map[string]interface{}{}
+	for _, dc := range configs {
+		if dc.config == "" {
+			continue
+		}
+		hash, err := applyDynamicConfig(sdk, m, dc)
+		if err != nil {
+			emitEvents.EmitEventGeneric(m, "DruidDynamicConfigApplyFail", "", fmt.Errorf("failed to apply %s due to [%s]", dc.name, err.Error()))
+			continue
+		}
+		if hash != dc.appliedHash {
+			fields[dc.statusField] = hash
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return druidStatusFieldsPatcher(sdk, fields, m, emitEvents)
+}
+
+// applyDynamicConfig merges the desired config into the current one and posts it if it changed or drifted.
+// The current config is kept as the base, so that fields managed outside of the spec, such as the
+// decommissioningNodes of the coordinator, are preserved. Returns the hash of the desired config.
+func applyDynamicConfig(sdk client.Client, m *v1alpha1.Druid, dc dynamicConfig) (string, error) {
+	desired := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(dc.config), &desired); err != nil {
+		return "", fmt.Errorf("invalid json due to [%s]", err.Error())
+	}
+	hash, err := getJSONHash(desired)
+	if err != nil {
+		return "", err
+	}
+
+	c, err := newDruidAPIClient(sdk, m, dc.nodeType)
+	if err != nil {
+		return "", err
+	}
+
+	if applied, err := mergeDynamicConfig(c, dc, desired, hash); err != nil {
+		return "", err
+	} else if applied {
+		logger.Info(fmt.Sprintf("Applied %s", dc.name), "name", m.Name, "namespace", m.Namespace)
+	}
+	return hash, nil
+}
+
+// mergeDynamicConfig posts the desired config merged into the current one, unless it was already applied and the
+// current one did not drift. Returns whether the config was posted.
+func mergeDynamicConfig(c *druidAPIClient, dc dynamicConfig, desired map[string]interface{}, hash string) (bool, error) {
+	current := map[string]interface{}{}
+	if _, err := c.do(http.MethodGet, dc.path, nil, &current); err != nil {
+		return false, err
+	}
+
+	if hash == dc.appliedHash && isJSONSubset(desired, current) {
+		return false, nil
+	}
+
+	merged := map[string]interface{}{}
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	if _, err := c.do(http.MethodPost, dc.path, merged, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,67 @@
+package druid
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// newDynamicConfigServer returns a client of a druid API serving the config, replaced by the POST requests.
+func newDynamicConfigServer(t *testing.T, config map[string]interface{}) (*druidAPIClient, *int) {
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts++
+			body, _ := io.ReadAll(r.Body)
+			for k := range config {
+				delete(config, k)
+			}
+			if err := json.Unmarshal(body, &config); err != nil {
+				t.Errorf("invalid posted config [%s]", string(body))
+			}
+			return
+		}
+		_ = json.NewEncoder(w).Encode(config)
+	}))
+	t.Cleanup(server.Close)
+	return &druidAPIClient{baseURL: server.URL, httpClient: server.Client()}, &posts
+}
+
+func TestMergeDynamicConfig(t *testing.T) {
+	config := map[string]interface{}{
+		"maxSegmentsToMove":    float64(5),
+		"decommissioningNodes": []interface{}{"historical-0:8083"},
+	}
+	c, posts := newDynamicConfigServer(t, config)
+	desired := map[string]interface{}{"maxSegmentsToMove": float64(100), "replicationThrottleLimit": float64(10)}
+	hash, _ := getJSONHash(desired)
+	dc := dynamicConfig{name: "coordinatorDynamicConfig", path: "/druid/coordinator/v1/config"}
+
+	if applied, err := mergeDynamicConfig(c, dc, desired, hash); err != nil || !applied {
+		t.Fatalf("config must be applied on first sync, got [%t] [%v]", applied, err)
+	}
+	expected := map[string]interface{}{
+		"maxSegmentsToMove":        float64(100),
+		"replicationThrottleLimit": float64(10),
+		"decommissioningNodes":     []interface{}{"historical-0:8083"},
+	}
+	if !reflect.DeepEqual(expected, config) {
+		t.Errorf("desired config must be merged into the current one, expected %v, got %v", expected, config)
+	}
+
+	dc.appliedHash = hash
+	if applied, _ := mergeDynamicConfig(c, dc, desired, hash); applied || *posts != 1 {
+		t.Errorf("config already applied must not be posted again")
+	}
+
+	config["maxSegmentsToMove"] = float64(5)
+	if applied, _ := mergeDynamicConfig(c, dc, desired, hash); !applied || *posts != 2 {
+		t.Errorf("drifted config must be applied again")
+	}
+	if config["maxSegmentsToMove"] != float64(100) || !reflect.DeepEqual(config["decommissioningNodes"], []interface{}{"historical-0:8083"}) {
+		t.Errorf("drifted config must be merged into the current one, got %v", config)
+	}
+}
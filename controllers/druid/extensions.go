@@ -0,0 +1,140 @@
+package druid
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	extensionsVolumeName       = "druid-extensions"
+	extensionsMountPath        = "/druid/extensions"
+	defaultExtensionsInitImage = "curlimages/curl:8.5.0"
+)
+
+// getExtensions returns the extensions of the cluster followed by the ones of the node spec, the node spec overriding
+// the extensions of the same name.
+func getExtensions(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) []v1alpha1.ExtensionSpec {
+	extensions := []v1alpha1.ExtensionSpec{}
+	indexes := map[string]int{}
+	for _, ext := range append(append([]v1alpha1.ExtensionSpec{}, m.Spec.Extensions...), nodeSpec.Extensions...) {
+		if i, ok := indexes[ext.Name]; ok {
+			extensions[i] = ext
+			continue
+		}
+		indexes[ext.Name] = len(extensions)
+		extensions = append(extensions, ext)
+	}
+	return extensions
+}
+
+func isExtraExtension(ext *v1alpha1.ExtensionSpec) bool {
+	return ext.Image != "" || ext.URL != ""
+}
+
+// getExtensionLoadListEntry returns the name of a core extension, or the absolute path of an extra extension as it
+// is not in druid.extensions.directory.
+func getExtensionLoadListEntry(ext *v1alpha1.ExtensionSpec) string {
+	if isExtraExtension(ext) {
+		return path.Join(extensionsMountPath, ext.Name)
+	}
+	return ext.Name
+}
+
+// makeExtensionsRuntimeProperties returns the loadList property of the node with its extensions added, or an empty
+// string if they are already loaded or if loadList is not set, druid loading all the core extensions then.
+// It is appended to the node properties to take precedence over the common ones.
+func makeExtensionsRuntimeProperties(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) (string, error) {
+	extensions := getExtensions(nodeSpec, m)
+	if len(extensions) == 0 {
+		return "", nil
+	}
+
+	discoveryProp, err := makeDiscoveryRuntimeProperties(m)
+	if err != nil {
+		return "", err
+	}
+	entries := make([]string, 0, len(extensions))
+	for i := range extensions {
+		entries = append(entries, getExtensionLoadListEntry(&extensions[i]))
+	}
+	return makeExtensionsLoadListProperty(
+		fmt.Sprintf("%s\n%s\n%s", m.Spec.CommonRuntimeProperties, discoveryProp, nodeSpec.RuntimeProperties), entries...)
+}
+
+// addExtensionsToPodSpec copies the extra extensions of the node spec into a shared volume with an init container per
+// extension, from their image or their URL.
+func addExtensionsToPodSpec(spec *v1.PodSpec, nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) {
+	mounted := false
+	for i, ext := range getExtensions(nodeSpec, m) {
+		if !isExtraExtension(&ext) {
+			continue
+		}
+		mounted = true
+
+		container := v1.Container{
+			Name:            fmt.Sprintf("extension-%d", i),
+			VolumeMounts:    []v1.VolumeMount{{Name: extensionsVolumeName, MountPath: extensionsMountPath}},
+			SecurityContext: spec.Containers[0].SecurityContext,
+		}
+		// paths and URL are given as arguments of the script so that they are not interpreted by the shell
+		dir := path.Join(extensionsMountPath, ext.Name)
+		if ext.URL != "" {
+			container.Image = firstNonEmptyStr(m.Spec.ExtensionsInitImage, defaultExtensionsInitImage)
+			container.Command = []string{"sh", "-c", `mkdir -p "$0" && curl -fsSL "$1" | tar -xzf - -C "$0"`, dir, ext.URL}
+		} else {
+			container.Image = ext.Image
+			container.Command = []string{"sh", "-c", `mkdir -p "$0" && cp -R "$1"/. "$0"`, dir, ext.Path}
+		}
+		spec.InitContainers = append(spec.InitContainers, container)
+	}
+	if !mounted {
+		return
+	}
+
+	spec.Volumes = append(spec.Volumes, v1.Volume{
+		Name:         extensionsVolumeName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+	})
+	druid := &spec.Containers[0]
+	druid.VolumeMounts = append(druid.VolumeMounts, v1.VolumeMount{Name: extensionsVolumeName, MountPath: extensionsMountPath, ReadOnly: true})
+}
+
+// validateExtensions returns an error if an extension has no name or a name escaping the extensions directory, or
+// if an extra extension is not given by either an image with a path or a URL.
+func validateExtensions(extensions []v1alpha1.ExtensionSpec) error {
+	for _, ext := range extensions {
+		switch {
+		case ext.Name == "":
+			return fmt.Errorf("extension missing name")
+		case strings.ContainsAny(ext.Name, "/\\") || strings.Contains(ext.Name, ".."):
+			return fmt.Errorf("extension name [%s] must not contain path separators or [..]", ext.Name)
+		case ext.Image != "" && ext.URL != "":
+			return fmt.Errorf("extension [%s] must set either image or url", ext.Name)
+		case ext.Image != "" && ext.Path == "":
+			return fmt.Errorf("extension [%s] missing path of the extension in image [%s]", ext.Name, ext.Image)
+		case ext.Image == "" && ext.Path != "":
+			return fmt.Errorf("extension [%s] path requires an image", ext.Name)
+		}
+	}
+	return nil
+}
+
+// validateExtensionsLoadList returns an error if the node spec has extra extensions without loadList. Druid loads all
+// the extensions of the extensions directory without loadList, but not the extra extensions loaded by path.
+func validateExtensionsLoadList(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) error {
+	for _, ext := range getExtensions(nodeSpec, m) {
+		if !isExtraExtension(&ext) {
+			continue
+		}
+		properties := fmt.Sprintf("%s\n%s", m.Spec.CommonRuntimeProperties, nodeSpec.RuntimeProperties)
+		if value, found := getPropertyValue(properties, extensionsLoadListProperty); !found || value == "" {
+			return fmt.Errorf("extension [%s] requires %s to be set", ext.Name, extensionsLoadListProperty)
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package druid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+func TestExtensions(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	clusterSpec.Spec.CommonRuntimeProperties = `druid.extensions.loadList=["druid-s3-extensions"]`
+	clusterSpec.Spec.Extensions = []v1alpha1.ExtensionSpec{
+		{Name: "druid-histogram"},
+		{Name: "druid-s3-extensions"},
+	}
+	brokers := clusterSpec.Spec.Nodes["brokers"]
+	brokers.Extensions = []v1alpha1.ExtensionSpec{
+		{Name: "druid-moving-average-query", Image: "example/druid-extensions:1.0", Path: "/extensions/druid-moving-average-query"},
+		{Name: "druid-histogram", URL: "https://example.com/druid-histogram.tar.gz"},
+	}
+
+	prop, err := makeExtensionsRuntimeProperties(&brokers, clusterSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `druid.extensions.loadList=["druid-s3-extensions","/druid/extensions/druid-histogram","/druid/extensions/druid-moving-average-query"]` + "\n"
+	if prop != expected {
+		t.Errorf("expected [%s], got [%s]", expected, prop)
+	}
+
+	spec := makePodSpec(&brokers, clusterSpec, "druid-druid-test-brokers", "")
+	if len(spec.InitContainers) != 2 {
+		t.Fatalf("expected an init container per extra extension, got %+v", spec.InitContainers)
+	}
+	download, copyFromImage := spec.InitContainers[0], spec.InitContainers[1]
+	if download.Image != defaultExtensionsInitImage || download.Command[4] != "https://example.com/druid-histogram.tar.gz" || download.Command[3] != "/druid/extensions/druid-histogram" {
+		t.Errorf("unexpected download init container %+v", download)
+	}
+	if copyFromImage.Image != "example/druid-extensions:1.0" || copyFromImage.Command[4] != "/extensions/druid-moving-average-query" {
+		t.Errorf("unexpected copy init container %+v", copyFromImage)
+	}
+	mount := spec.Containers[0].VolumeMounts[len(spec.Containers[0].VolumeMounts)-1]
+	if mount.Name != extensionsVolumeName || mount.MountPath != extensionsMountPath || !mount.ReadOnly {
+		t.Errorf("unexpected extensions mount %+v", mount)
+	}
+
+	historicals := clusterSpec.Spec.Nodes["historicals"]
+	if spec := makePodSpec(&historicals, clusterSpec, "druid-druid-test-historicals", ""); len(spec.InitContainers) != 0 {
+		t.Errorf("core extensions must not add init containers, got %+v", spec.InitContainers)
+	}
+
+	brokers.Extensions = append(brokers.Extensions, v1alpha1.ExtensionSpec{Name: "druid-foo", Image: "example/druid-foo:1.0"})
+	clusterSpec.Spec.Nodes["brokers"] = brokers
+	if _, err := verifyDruidSpec(clusterSpec); err == nil || !strings.Contains(err.Error(), "Node[brokers] extension [druid-foo] missing path") {
+		t.Errorf("extension image without path must be rejected, got [%v]", err)
+	}
+}
+
+func TestExtensionsWithoutLoadList(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	clusterSpec.Spec.CommonRuntimeProperties = "druid.zk.service.host=zookeeper"
+	clusterSpec.Spec.Extensions = []v1alpha1.ExtensionSpec{{Name: "druid-histogram"}}
+	brokers := clusterSpec.Spec.Nodes["brokers"]
+	brokers.RuntimeProperties = "druid.service=druid/broker"
+
+	// druid loads all the core extensions without loadList
+	if prop, err := makeExtensionsRuntimeProperties(&brokers, clusterSpec); err != nil || prop != "" {
+		t.Errorf("loadList must not be set without loadList, got [%s] [%v]", prop, err)
+	}
+
+	brokers.Extensions = []v1alpha1.ExtensionSpec{{Name: "druid-example", URL: "https://example.com/druid-example.tar.gz"}}
+	clusterSpec.Spec.Nodes["brokers"] = brokers
+	if _, err := verifyDruidSpec(clusterSpec); err == nil || !strings.Contains(err.Error(), "Node[brokers] extension [druid-example] requires druid.extensions.loadList to be set") {
+		t.Errorf("extra extensions without loadList must be rejected, got [%v]", err)
+	}
+
+	for _, name := range []string{"..", "../etc", "a/b", `a\b`} {
+		if err := validateExtensions([]v1alpha1.ExtensionSpec{{Name: name, URL: "https://example.com/ext.tar.gz"}}); err == nil {
+			t.Errorf("extension name [%s] must be rejected", name)
+		}
+	}
+}
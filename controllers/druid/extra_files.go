@@ -0,0 +1,43 @@
+package druid
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// addExtraFiles adds the extra files to the data of a generated ConfigMap, the files generated by the operator not
+// being overwritten.
+func addExtraFiles(data map[string]string, files map[string]string, field string) error {
+	if err := validateExtraFiles(files, field); err != nil {
+		return err
+	}
+	for _, name := range getExtraFileNames(files) {
+		if _, ok := data[name]; ok {
+			return fmt.Errorf("file [%s] of %s overwrites a config file generated by the operator", name, field)
+		}
+		data[name] = files[name]
+	}
+	return nil
+}
+
+// validateExtraFiles returns an error if a file name is not a valid ConfigMap key.
+func validateExtraFiles(files map[string]string, field string) error {
+	for _, name := range getExtraFileNames(files) {
+		if errs := validation.IsConfigMapKey(name); len(errs) > 0 {
+			return fmt.Errorf("invalid file name [%s] in %s due to [%s]", name, field, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+func getExtraFileNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,41 @@
+package druid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtraFiles(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	clusterSpec.Spec.ExtraCommonFiles = map[string]string{"core-site.xml": "<configuration/>"}
+	brokers := clusterSpec.Spec.Nodes["brokers"]
+	brokers.ExtraNodeFiles = map[string]string{"basic-security.properties": "druid.auth.authenticatorChain=[]"}
+
+	commonConfig, err := makeCommonConfigMap(clusterSpec, makeLabelsForDruid(clusterSpec.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commonConfig.Data["core-site.xml"] != "<configuration/>" || commonConfig.Data["common.runtime.properties"] == "" {
+		t.Errorf("unexpected common config %v", commonConfig.Data)
+	}
+
+	uniq := makeNodeSpecificUniqueString(clusterSpec, "brokers")
+	nodeConfig, err := makeConfigMapForNodeSpec(&brokers, clusterSpec, makeLabelsForNodeSpec(&brokers, clusterSpec, clusterSpec.Name, uniq), uniq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodeConfig.Data["basic-security.properties"] != "druid.auth.authenticatorChain=[]" || nodeConfig.Data["runtime.properties"] == "" {
+		t.Errorf("unexpected node config %v", nodeConfig.Data)
+	}
+
+	brokers.ExtraNodeFiles = map[string]string{"runtime.properties": "druid.port=1"}
+	if _, err := makeConfigMapForNodeSpec(&brokers, clusterSpec, nil, uniq); err == nil || !strings.Contains(err.Error(), "overwrites a config file generated by the operator") {
+		t.Errorf("generated files must not be overwritten, got [%v]", err)
+	}
+
+	brokers.ExtraNodeFiles = map[string]string{"conf/log4j2.xml": ""}
+	clusterSpec.Spec.Nodes["brokers"] = brokers
+	if _, err := verifyDruidSpec(clusterSpec); err == nil || !strings.Contains(err.Error(), "Node[brokers] invalid file name [conf/log4j2.xml] in extraNodeFiles") {
+		t.Errorf("invalid file names must be rejected, got [%v]", err)
+	}
+}
@@ -7,11 +7,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
+	"strings"
 
 	autoscalev2beta2 "k8s.io/api/autoscaling/v2beta2"
 	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	storage "k8s.io/api/storage/v1"
 
 	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
@@ -21,6 +25,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -39,6 +44,8 @@ const (
 	router                       = "router"
 	defaultCommonConfigMountPath = "/druid/conf/druid/_common"
 	finalizerName                = "deletepvc.finalizers.druid.apache.org"
+	restartedAtAnnotation        = "druid.apache.org/restartedAt"
+	nodeRestartedAtAnnotation    = "druid.apache.org/nodeRestartedAt"
 )
 
 var logger = logf.Log.WithName("druid_operator_handler")
@@ -48,12 +55,55 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 		return nil
 	}
 
-	if err := verifyDruidSpec(m); err != nil {
+	/*
+		Default Behavior: Finalizer shall be always executed resulting in deletion of pvc post deletion of Druid CR
+		When the object (druid CR) has for deletion time stamp set, execute the finalizer
+		Finalizer shall execute the following flow :
+		1. Get sts List and PVC List
+		2. Range and Delete sts first and then delete pvc. PVC must be deleted after sts termination has been executed
+			else pvc finalizer shall block deletion since a pod/sts is referencing it.
+		3. Once delete is executed we block program and return.
+		The finalizer is handled before the spec is validated, so that CRs rejected by newer validations keep their
+		cleanup on deletion.
+	*/
+
+	// task Jobs of the kubernetes task runner are not owned by the CR, finalizer garbage collects them
+	if m.Spec.DisablePVCDeletionFinalizer == false || hasKubernetesTaskRunner(m) {
+		md := m.GetDeletionTimestamp() != nil
+		if md {
+			return executeFinalizers(sdk, m, emitEvents)
+		}
+		/*
+			If finalizer isn't present add it to object meta.
+			In case cr is already deleted do not call this function
+		*/
+		cr := checkIfCRExists(sdk, m, emitEvents)
+		if cr {
+			if !ContainsString(m.ObjectMeta.Finalizers, finalizerName) {
+				m.SetFinalizers(append(m.GetFinalizers(), finalizerName))
+				_, err := writers.Update(context.Background(), sdk, m, m, emitEvents)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if rolledBack, err := rollbackDruidSpec(sdk, m, emitEvents); err != nil || rolledBack {
+		return err
+	}
+
+	lintIssues, err := verifyDruidSpec(m)
+	if err != nil {
 		e := fmt.Errorf("invalid DruidSpec[%s:%s] due to [%s]", m.Kind, m.Name, err.Error())
 		emitEvents.EmitEventGeneric(m, "DruidOperatorInvalidSpec", "", e)
 		return nil
 	}
 
+	if err := emitConfigLintWarnings(sdk, m, lintIssues, emitEvents); err != nil {
+		return err
+	}
+
 	allNodeSpecs, err := getAllNodeSpecsInDruidPrescribedOrder(m)
 	if err != nil {
 		e := fmt.Errorf("invalid DruidSpec[%s:%s] due to [%s]", m.Kind, m.Name, err.Error())
@@ -61,6 +111,10 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 		return nil
 	}
 
+	if err := recordDruidSpecRevision(sdk, m, emitEvents); err != nil {
+		return err
+	}
+
 	statefulSetNames := make(map[string]bool)
 	deploymentNames := make(map[string]bool)
 	serviceNames := make(map[string]bool)
@@ -69,6 +123,10 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 	hpaNames := make(map[string]bool)
 	ingressNames := make(map[string]bool)
 	pvcNames := make(map[string]bool)
+	serviceAccountNames := make(map[string]bool)
+	roleNames := make(map[string]bool)
+	roleBindingNames := make(map[string]bool)
+	certificateNames := make(map[string]bool)
 
 	ls := makeLabelsForDruid(m.Name)
 
@@ -80,6 +138,10 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 	if err != nil {
 		return err
 	}
+	commonConfigFromSHA, err := getConfigFromHash(sdk, m, m.Spec.ConfigFrom, commonConfig, emitEvents)
+	if err != nil {
+		return err
+	}
 
 	if _, err := sdkCreateOrUpdateAsNeeded(sdk,
 		func() (object, error) { return makeCommonConfigMap(m, ls) },
@@ -88,35 +150,8 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 		return err
 	}
 
-	/*
-		Default Behavior: Finalizer shall be always executed resulting in deletion of pvc post deletion of Druid CR
-		When the object (druid CR) has for deletion time stamp set, execute the finalizer
-		Finalizer shall execute the following flow :
-		1. Get sts List and PVC List
-		2. Range and Delete sts first and then delete pvc. PVC must be deleted after sts termination has been executed
-			else pvc finalizer shall block deletion since a pod/sts is referencing it.
-		3. Once delete is executed we block program and return.
-	*/
-
-	if m.Spec.DisablePVCDeletionFinalizer == false {
-		md := m.GetDeletionTimestamp() != nil
-		if md {
-			return executeFinalizers(sdk, m, emitEvents)
-		}
-		/*
-			If finalizer isn't present add it to object meta.
-			In case cr is already deleted do not call this function
-		*/
-		cr := checkIfCRExists(sdk, m, emitEvents)
-		if cr {
-			if !ContainsString(m.ObjectMeta.Finalizers, finalizerName) {
-				m.SetFinalizers(append(m.GetFinalizers(), finalizerName))
-				_, err := writers.Update(context.Background(), sdk, m, m, emitEvents)
-				if err != nil {
-					return err
-				}
-			}
-		}
+	if err := deployDiscoveryRBAC(sdk, m, roleNames, roleBindingNames, emitEvents); err != nil {
+		return err
 	}
 
 	for _, elem := range allNodeSpecs {
@@ -129,6 +164,16 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 
 		lm := makeLabelsForNodeSpec(&nodeSpec, m, m.Name, nodeSpecUniqueStr)
 
+		// segment cache volume size and properties grown by the operator apply to the config and the statefulset
+		if isAutoExpanded(&nodeSpec) {
+			if err := reconcileVolumeAutoExpand(sdk, &nodeSpec, key, nodeSpecUniqueStr, lm, m, emitEvents); err != nil {
+				return err
+			}
+			if err := applyVolumeAutoExpand(&nodeSpec, m, key); err != nil {
+				return err
+			}
+		}
+
 		// create configmap first
 		nodeConfig, err := makeConfigMapForNodeSpec(&nodeSpec, m, lm, nodeSpecUniqueStr)
 		if err != nil {
@@ -140,6 +185,27 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 			return err
 		}
 
+		nodeConfigFromSHA, err := getConfigFromHash(sdk, m, nodeSpec.ConfigFrom, nodeConfig, emitEvents)
+		if err != nil {
+			return err
+		}
+
+		tlsSHA, err := reconcileDruidTLS(sdk, &nodeSpec, m, nodeSpecUniqueStr, lm, certificateNames, emitEvents)
+		if err != nil {
+			return err
+		}
+
+		// pods are restarted on changes of the configs, of the files from configFrom, or on rotation of the certificate
+		configMapSHA := fmt.Sprintf("%s-%s", commonConfigSHA, nodeConfigSHA)
+		for _, sha := range []string{commonConfigFromSHA, nodeConfigFromSHA} {
+			if sha != "" {
+				configMapSHA = fmt.Sprintf("%s-%s", configMapSHA, sha)
+			}
+		}
+		if tlsSHA != "" {
+			configMapSHA = fmt.Sprintf("%s-%s", configMapSHA, tlsSHA)
+		}
+
 		if _, err := sdkCreateOrUpdateAsNeeded(sdk,
 			func() (object, error) { return nodeConfig, nil },
 			func() object { return makeConfigMapEmptyObj() },
@@ -147,12 +213,21 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 			return err
 		}
 
+		if err := deployTaskRunner(sdk, &nodeSpec, m, lm, nodeSpecUniqueStr, configMapNames, serviceAccountNames, roleNames, roleBindingNames, emitEvents); err != nil {
+			return err
+		}
+
 		//create services before creating statefulset
 		firstServiceName := ""
 		services := firstNonNilValue(nodeSpec.Services, m.Spec.Services).([]v1.Service)
 		for _, svc := range services {
 			if _, err := sdkCreateOrUpdateAsNeeded(sdk,
-				func() (object, error) { return makeService(&svc, &nodeSpec, m, lm, nodeSpecUniqueStr) },
+				func() (object, error) {
+					if isBlueGreen(&nodeSpec) {
+						return makeBlueGreenService(&svc, &nodeSpec, m, lm, nodeSpecUniqueStr, getBlueGreenSelectedColor(m, key))
+					}
+					return makeService(&svc, &nodeSpec, m, lm, nodeSpecUniqueStr)
+				},
 				func() object { return makeServiceEmptyObj() }, alwaysTrueIsEqualsFn,
 				func(prev, curr object) { (curr.(*v1.Service)).Spec.ClusterIP = (prev.(*v1.Service)).Spec.ClusterIP },
 				m, serviceNames, emitEvents); err != nil {
@@ -165,10 +240,25 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 
 		nodeSpec.Ports = append(nodeSpec.Ports, v1.ContainerPort{ContainerPort: nodeSpec.DruidPort, Name: "druid-port"})
 
-		if nodeSpec.Kind == "Deployment" {
+		if isTaskAutoscaled(&nodeSpec) {
+			replicas, err := reconcileTaskAutoscaler(sdk, &nodeSpec, key, nodeSpecUniqueStr, lm, m, emitEvents)
+			if err != nil {
+				return err
+			}
+			nodeSpec.Replicas = replicas
+		}
+
+		if isBlueGreen(&nodeSpec) {
+			if done, err := deployBlueGreen(sdk, &nodeSpec, key, nodeSpecUniqueStr, configMapSHA, firstServiceName, m, lm, deploymentNames, emitEvents); err != nil {
+				return err
+			} else if !done && m.Spec.RollingDeploy {
+				// blue/green rollout of this node is in progress, stop here
+				return nil
+			}
+		} else if nodeSpec.Kind == "Deployment" {
 			if deployCreateUpdateStatus, err := sdkCreateOrUpdateAsNeeded(sdk,
 				func() (object, error) {
-					return makeDeployment(&nodeSpec, m, lm, nodeSpecUniqueStr, fmt.Sprintf("%s-%s", commonConfigSHA, nodeConfigSHA), firstServiceName)
+					return makeDeployment(&nodeSpec, m, lm, nodeSpecUniqueStr, configMapSHA, firstServiceName)
 				},
 				func() object { return makeDeploymentEmptyObj() },
 				deploymentIsEquals, noopUpdaterFn, m, deploymentNames, emitEvents); err != nil {
@@ -194,20 +284,27 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 
 			//	scalePVCForSTS to be only called only if volumeExpansion is supported by the storage class.
 			//  Ignore for the first iteration ie cluster creation, else get sts shall unnecessary log errors.
+			//  Volumes grown by the operator are resized whatever the generation, as status patches don't bump it.
 
 			if m.Generation > 1 && m.Spec.ScalePvcSts {
 				if isVolumeExpansionEnabled(sdk, m, &nodeSpec, emitEvents) {
-					err := scalePVCForSts(sdk, &nodeSpec, nodeSpecUniqueStr, m, emitEvents)
+					err := scalePVCForSts(sdk, &nodeSpec, nodeSpecUniqueStr, m, nil, emitEvents)
 					if err != nil {
 						return err
 					}
 				}
+			} else if isVolumeAutoExpanded(&nodeSpec, m, key) {
+				// the storage class of the grown volume allows expansion, checked before growing it
+				vctName := nodeSpec.VolumeClaimTemplates[getAutoExpandVolumeClaimTemplateIndex(&nodeSpec)].Name
+				if err := scalePVCForSts(sdk, &nodeSpec, nodeSpecUniqueStr, m, []string{vctName}, emitEvents); err != nil {
+					return err
+				}
 			}
 
 			// Create/Update StatefulSet
 			if stsCreateUpdateStatus, err := sdkCreateOrUpdateAsNeeded(sdk,
 				func() (object, error) {
-					return makeStatefulSet(&nodeSpec, m, lm, nodeSpecUniqueStr, fmt.Sprintf("%s-%s", commonConfigSHA, nodeConfigSHA), firstServiceName)
+					return makeStatefulSet(&nodeSpec, m, lm, nodeSpecUniqueStr, configMapSHA, firstServiceName)
 				},
 				func() object { return makeStatefulSetEmptyObj() },
 				statefulSetIsEquals, noopUpdaterFn, m, statefulSetNames, emitEvents); err != nil {
@@ -264,6 +361,9 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 		if nodeSpec.HPAutoScaler != nil {
 			if _, err := sdkCreateOrUpdateAsNeeded(sdk,
 				func() (object, error) {
+					if isBlueGreen(&nodeSpec) {
+						return makeBlueGreenHorizontalPodAutoscaler(&nodeSpec, m, ls, nodeSpecUniqueStr, getBlueGreenSelectedColor(m, key))
+					}
 					return makeHorizontalPodAutoscaler(&nodeSpec, m, ls, nodeSpecUniqueStr)
 				},
 				func() object { return makeHorizontalPodAutoscalerEmptyObj() },
@@ -379,6 +479,52 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 		}, emitEvents)
 	sort.Strings(updatedStatus.ConfigMaps)
 
+	deleteUnusedResources(sdk, m, serviceAccountNames, ls,
+		func() objectList { return makeServiceAccountListEmptyObj() },
+		func(listObj runtime.Object) []object {
+			items := listObj.(*v1.ServiceAccountList).Items
+			result := make([]object, len(items))
+			for i := 0; i < len(items); i++ {
+				result[i] = &items[i]
+			}
+			return result
+		}, emitEvents)
+
+	deleteUnusedResources(sdk, m, roleBindingNames, ls,
+		func() objectList { return makeRoleBindingListEmptyObj() },
+		func(listObj runtime.Object) []object {
+			items := listObj.(*rbacv1.RoleBindingList).Items
+			result := make([]object, len(items))
+			for i := 0; i < len(items); i++ {
+				result[i] = &items[i]
+			}
+			return result
+		}, emitEvents)
+
+	if isCertManagerInstalled(sdk) {
+		deleteUnusedResources(sdk, m, certificateNames, ls,
+			func() objectList { return makeCertManagerCertificateListEmptyObj() },
+			func(listObj runtime.Object) []object {
+				items := listObj.(*unstructured.UnstructuredList).Items
+				result := make([]object, len(items))
+				for i := 0; i < len(items); i++ {
+					result[i] = &items[i]
+				}
+				return result
+			}, emitEvents)
+	}
+
+	deleteUnusedResources(sdk, m, roleNames, ls,
+		func() objectList { return makeRoleListEmptyObj() },
+		func(listObj runtime.Object) []object {
+			items := listObj.(*rbacv1.RoleList).Items
+			result := make([]object, len(items))
+			for i := 0; i < len(items); i++ {
+				result[i] = &items[i]
+			}
+			return result
+		}, emitEvents)
+
 	podList, _ := readers.List(context.TODO(), sdk, m, makeLabelsForDruid(m.Name), emitEvents, func() objectList { return makePodList() }, func(listObj runtime.Object) []object {
 		items := listObj.(*v1.PodList).Items
 		result := make([]object, len(items))
@@ -389,6 +535,14 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 	})
 
 	updatedStatus.Pods = getPodNames(podList)
+	updatedStatus.BlueGreen = m.Status.BlueGreen
+	updatedStatus.CurrentRevision = m.Status.CurrentRevision
+	updatedStatus.PreviousRevision = m.Status.PreviousRevision
+	updatedStatus.CoordinatorDynamicConfigHash = m.Status.CoordinatorDynamicConfigHash
+	updatedStatus.OverlordDynamicConfigHash = m.Status.OverlordDynamicConfigHash
+	updatedStatus.TaskAutoscaler = m.Status.TaskAutoscaler
+	updatedStatus.AutoExpand = m.Status.AutoExpand
+	updatedStatus.ConfigLintWarningsHash = m.Status.ConfigLintWarningsHash
 	sort.Strings(updatedStatus.Pods)
 
 	// All druid nodes are in Ready state.
@@ -410,6 +564,12 @@ func deployDruidCluster(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEm
 		return err
 	}
 
+	if updatedStatus.DruidNodeStatus.DruidNodeConditionType == v1alpha1.DruidClusterReady {
+		if err := syncDruidDynamicConfigs(sdk, m, emitEvents); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -455,18 +615,7 @@ func deleteOrphanPVC(sdk client.Client, drd *v1alpha1.Druid, emitEvents EventEmi
 		return err
 	}
 
-	pvcLabels := map[string]string{
-		"druid_cr": drd.Name,
-	}
-
-	pvcList, err := readers.List(context.TODO(), sdk, drd, pvcLabels, emitEvents, func() objectList { return makePersistentVolumeClaimListEmptyObj() }, func(listObj runtime.Object) []object {
-		items := listObj.(*v1.PersistentVolumeClaimList).Items
-		result := make([]object, len(items))
-		for i := 0; i < len(items); i++ {
-			result[i] = &items[i]
-		}
-		return result
-	})
+	pvcList, err := listPersistentVolumeClaims(sdk, drd, makeLabelsForPVC(drd), emitEvents)
 	if err != nil {
 		return err
 	}
@@ -514,21 +663,39 @@ func deleteOrphanPVC(sdk client.Client, drd *v1alpha1.Druid, emitEvents EventEmi
 	return nil
 }
 
+// makeLabelsForPVC returns the labels selecting the pvcs of the druid CR, pvcs are labelled by the statefulset selector.
+func makeLabelsForPVC(drd *v1alpha1.Druid) map[string]string {
+	return map[string]string{
+		"druid_cr": drd.Name,
+	}
+}
+
+// listPersistentVolumeClaims returns the pvcs matching the selector labels in the namespace of the druid CR.
+func listPersistentVolumeClaims(sdk client.Client, drd *v1alpha1.Druid, selectorLabels map[string]string, emitEvents EventEmitter) ([]object, error) {
+	return readers.List(context.TODO(), sdk, drd, selectorLabels, emitEvents, func() objectList { return makePersistentVolumeClaimListEmptyObj() }, func(listObj runtime.Object) []object {
+		items := listObj.(*v1.PersistentVolumeClaimList).Items
+		result := make([]object, len(items))
+		for i := 0; i < len(items); i++ {
+			result[i] = &items[i]
+		}
+		return result
+	})
+}
+
 func executeFinalizers(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEmitter) error {
 
-	if ContainsString(m.ObjectMeta.Finalizers, finalizerName) {
-		pvcLabels := map[string]string{
-			"druid_cr": m.Name,
+	if !ContainsString(m.ObjectMeta.Finalizers, finalizerName) {
+		return nil
+	}
+
+	if hasKubernetesTaskRunner(m) {
+		if err := deleteTaskRunnerJobs(sdk, m, emitEvents); err != nil {
+			return err
 		}
+	}
 
-		pvcList, err := readers.List(context.TODO(), sdk, m, pvcLabels, emitEvents, func() objectList { return makePersistentVolumeClaimListEmptyObj() }, func(listObj runtime.Object) []object {
-			items := listObj.(*v1.PersistentVolumeClaimList).Items
-			result := make([]object, len(items))
-			for i := 0; i < len(items); i++ {
-				result[i] = &items[i]
-			}
-			return result
-		})
+	if m.Spec.DisablePVCDeletionFinalizer == false {
+		pvcList, err := listPersistentVolumeClaims(sdk, m, makeLabelsForPVC(m), emitEvents)
 		if err != nil {
 			return err
 		}
@@ -555,18 +722,13 @@ func executeFinalizers(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEmi
 			//			sendEvent(sdk, m, v1.EventTypeNormal, DruidFinalizerSuccess, msg)
 			logger.Info(msg)
 		}
-
-		// remove our finalizer from the list and update it.
-		m.ObjectMeta.Finalizers = RemoveString(m.ObjectMeta.Finalizers, finalizerName)
-
-		_, err = writers.Update(context.TODO(), sdk, m, m, emitEvents)
-		if err != nil {
-			return err
-		}
-
 	}
-	return nil
 
+	// remove our finalizer from the list and update it.
+	m.ObjectMeta.Finalizers = RemoveString(m.ObjectMeta.Finalizers, finalizerName)
+
+	_, err := writers.Update(context.TODO(), sdk, m, m, emitEvents)
+	return err
 }
 
 func execCheckCrashStatus(sdk client.Client, nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, event EventEmitter) {
@@ -654,6 +816,17 @@ func alwaysTrueIsEqualsFn(prev, curr object) bool {
 	return true
 }
 
+// roleIsEquals returns true if the rules of the Roles are equal, to revert changes made out of the operator.
+func roleIsEquals(prev, curr object) bool {
+	return reflect.DeepEqual(prev.(*rbacv1.Role).Rules, curr.(*rbacv1.Role).Rules)
+}
+
+// roleBindingIsEquals returns true if the RoleBindings bind the same Role to the same subjects.
+func roleBindingIsEquals(prev, curr object) bool {
+	p, c := prev.(*rbacv1.RoleBinding), curr.(*rbacv1.RoleBinding)
+	return p.RoleRef == c.RoleRef && reflect.DeepEqual(p.Subjects, c.Subjects)
+}
+
 func noopUpdaterFn(prev, curr object) {
 	// do nothing
 }
@@ -744,8 +917,9 @@ func isObjFullyDeployed(sdk client.Client, nodeSpec v1alpha1.DruidNodeSpec, node
 }
 
 // scalePVCForSts shall expand the sts volumeclaimtemplates size as well as N no of pvc supported by the sts.
+// Only the volumeClaimTemplates are expanded if set, all the ones of the node spec otherwise.
 // NOTE: To be called only if generation > 1
-func scalePVCForSts(sdk client.Client, nodeSpec *v1alpha1.DruidNodeSpec, nodeSpecUniqueStr string, drd *v1alpha1.Druid, emitEvent EventEmitter) error {
+func scalePVCForSts(sdk client.Client, nodeSpec *v1alpha1.DruidNodeSpec, nodeSpecUniqueStr string, drd *v1alpha1.Druid, volumeClaimTemplates []string, emitEvent EventEmitter) error {
 
 	getSTSList, err := readers.List(context.TODO(), sdk, drd, makeLabelsForDruid(drd.Name), emitEvent, func() objectList { return makeStatefulSetListEmptyObj() }, func(listObj runtime.Object) []object {
 		items := listObj.(*appsv1.StatefulSetList).Items
@@ -775,50 +949,37 @@ func scalePVCForSts(sdk client.Client, nodeSpec *v1alpha1.DruidNodeSpec, nodeSpe
 		return nil
 	}
 
-	pvcLabels := map[string]string{
-		"component": nodeSpec.NodeType,
-	}
-
-	pvcList, err := readers.List(context.TODO(), sdk, drd, pvcLabels, emitEvent, func() objectList { return makePersistentVolumeClaimListEmptyObj() }, func(listObj runtime.Object) []object {
-		items := listObj.(*v1.PersistentVolumeClaimList).Items
-		result := make([]object, len(items))
-		for i := 0; i < len(items); i++ {
-			result[i] = &items[i]
-		}
-		return result
-	})
+	pvcList, err := listPersistentVolumeClaims(sdk, drd, makeLabelsForPVCOfNodeSpec(drd, nodeSpecUniqueStr), emitEvent)
 	if err != nil {
 		return nil
 	}
 
-	desVolumeClaimTemplateSize, currVolumeClaimTemplateSize, pvcSize := getVolumeClaimTemplateSizes(sts, nodeSpec, pvcList)
-
-	// current number of PVC can't be less than desired number of pvc
-	if len(pvcSize) < len(desVolumeClaimTemplateSize) {
-		return nil
-	}
-
-	// iterate over array for matching each index in desVolumeClaimTemplateSize, currVolumeClaimTemplateSize and pvcSize
-	for i := range desVolumeClaimTemplateSize {
+	for _, vct := range nodeSpec.VolumeClaimTemplates {
+		if len(volumeClaimTemplates) > 0 && !ContainsString(volumeClaimTemplates, vct.Name) {
+			continue
+		}
 
 		// Validate Request, shrinking of pvc not supported
 		// desired size cant be less than current size
 		// in that case re-create sts/pvc which is a user execute manual step
 
-		desiredSize, _ := desVolumeClaimTemplateSize[i].AsInt64()
-		currentSize, _ := currVolumeClaimTemplateSize[i].AsInt64()
+		desiredSize := vct.Spec.Resources.Requests[v1.ResourceStorage]
+		currentSize, found := getVolumeClaimTemplateSize(sts, vct.Name)
+		if !found {
+			continue
+		}
 
-		if desiredSize < currentSize {
+		if desiredSize.Cmp(currentSize) < 0 {
 			e := fmt.Errorf("Request for Shrinking of sts pvc size [sts:%s] in [namespace:%s] is not Supported", sts.(*appsv1.StatefulSet).Name, sts.(*appsv1.StatefulSet).Namespace)
 			logger.Error(e, e.Error(), "name", drd.Name, "namespace", drd.Namespace)
-			emitEvent.EmitEventGeneric(drd, "DruidOperatorPvcReSizeFail", "", err)
+			emitEvent.EmitEventGeneric(drd, "DruidOperatorPvcReSizeFail", "", e)
 			return e
 		}
 
 		// In case size dont match and dessize > currsize, delete the sts using casacde=false / propagation policy set to orphan
 		// The operator on next reconcile shall create the sts with latest changes
-		if desiredSize != currentSize {
-			msg := fmt.Sprintf("Detected Change in VolumeClaimTemplate Sizes for Statefuleset [%s] in Namespace [%s], desVolumeClaimTemplateSize: [%s], currVolumeClaimTemplateSize: [%s]\n, deleteing STS [%s] with casacde=false]", sts.(*appsv1.StatefulSet).Name, sts.(*appsv1.StatefulSet).Namespace, desVolumeClaimTemplateSize[i].String(), currVolumeClaimTemplateSize[i].String(), sts.(*appsv1.StatefulSet).Name)
+		if desiredSize.Cmp(currentSize) != 0 {
+			msg := fmt.Sprintf("Detected Change in VolumeClaimTemplate Sizes for Statefuleset [%s] in Namespace [%s], desVolumeClaimTemplateSize: [%s], currVolumeClaimTemplateSize: [%s]\n, deleteing STS [%s] with casacde=false]", sts.(*appsv1.StatefulSet).Name, sts.(*appsv1.StatefulSet).Namespace, desiredSize.String(), currentSize.String(), sts.(*appsv1.StatefulSet).Name)
 			logger.Info(msg)
 			emitEvent.EmitEventGeneric(drd, "DruidOperatorPvcReSizeDetected", msg, nil)
 
@@ -832,17 +993,18 @@ func scalePVCForSts(sdk client.Client, nodeSpec *v1alpha1.DruidNodeSpec, nodeSpe
 
 		}
 
-		// In case size dont match, patch the pvc with the desiredsize from druid CR
-		for p := range pvcSize {
-			pSize, _ := pvcSize[p].AsInt64()
-			if desiredSize != pSize {
+		// In case size dont match, patch the pvcs of the volume claim template with the desiredsize from druid CR
+		for _, obj := range getVolumeClaimTemplatePVCs(pvcList, vct.Name, sts.GetName()) {
+			pvc := obj.(*v1.PersistentVolumeClaim)
+			pvcSize := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+			if desiredSize.Cmp(pvcSize) > 0 {
 				// use deepcopy
-				patch := client.MergeFrom(pvcList[p].(*v1.PersistentVolumeClaim).DeepCopy())
-				pvcList[p].(*v1.PersistentVolumeClaim).Spec.Resources.Requests[v1.ResourceStorage] = desVolumeClaimTemplateSize[i]
-				if err := writers.Patch(context.TODO(), sdk, drd, pvcList[p].(*v1.PersistentVolumeClaim), false, patch, emitEvent); err != nil {
+				patch := client.MergeFrom(pvc.DeepCopy())
+				pvc.Spec.Resources.Requests[v1.ResourceStorage] = desiredSize
+				if err := writers.Patch(context.TODO(), sdk, drd, pvc, false, patch, emitEvent); err != nil {
 					return err
 				} else {
-					msg := fmt.Sprintf("[PVC:%s] successfully Patched with [Size:%s]", pvcList[p].(*v1.PersistentVolumeClaim).Name, desVolumeClaimTemplateSize[i].String())
+					msg := fmt.Sprintf("[PVC:%s] successfully Patched with [Size:%s]", pvc.Name, desiredSize.String())
 					logger.Info(msg, "name", drd.Name, "namespace", drd.Namespace)
 				}
 			}
@@ -853,26 +1015,28 @@ func scalePVCForSts(sdk client.Client, nodeSpec *v1alpha1.DruidNodeSpec, nodeSpe
 	return nil
 }
 
-// desVolumeClaimTemplateSize: the druid CR holds this value for a sts volumeclaimtemplate
-// currVolumeClaimTemplateSize: the sts owned by druid CR holds this value in volumeclaimtemplate
-// pvcSize: the pvc referenced by the sts holds this value
-// type of vars is resource.Quantity. ref: https://godoc.org/k8s.io/apimachinery/pkg/api/resource
-func getVolumeClaimTemplateSizes(sts object, nodeSpec *v1alpha1.DruidNodeSpec, pvc []object) (desVolumeClaimTemplateSize, currVolumeClaimTemplateSize, pvcSize []resource.Quantity) {
-
-	for i := range nodeSpec.VolumeClaimTemplates {
-		desVolumeClaimTemplateSize = append(desVolumeClaimTemplateSize, nodeSpec.VolumeClaimTemplates[i].Spec.Resources.Requests[v1.ResourceStorage])
-	}
-
-	for i := range sts.(*appsv1.StatefulSet).Spec.VolumeClaimTemplates {
-		currVolumeClaimTemplateSize = append(currVolumeClaimTemplateSize, sts.(*appsv1.StatefulSet).Spec.VolumeClaimTemplates[i].Spec.Resources.Requests[v1.ResourceStorage])
+// getVolumeClaimTemplateSize returns the size requested by the volume claim template of the sts.
+func getVolumeClaimTemplateSize(sts object, name string) (resource.Quantity, bool) {
+	for _, vct := range sts.(*appsv1.StatefulSet).Spec.VolumeClaimTemplates {
+		if vct.Name == name {
+			return vct.Spec.Resources.Requests[v1.ResourceStorage], true
+		}
 	}
+	return resource.Quantity{}, false
+}
 
-	for i := range pvc {
-		pvcSize = append(pvcSize, pvc[i].(*v1.PersistentVolumeClaim).Spec.Resources.Requests[v1.ResourceStorage])
+// getVolumeClaimTemplatePVCs returns the pvcs created by the sts from the volume claim template, named
+// <template>-<sts>-<ordinal>.
+func getVolumeClaimTemplatePVCs(pvcList []object, vctName, stsName string) []object {
+	result := []object{}
+	for _, pvc := range pvcList {
+		if ordinal := strings.TrimPrefix(pvc.GetName(), vctName+"-"+stsName+"-"); ordinal != pvc.GetName() {
+			if _, err := strconv.Atoi(ordinal); err == nil {
+				result = append(result, pvc)
+			}
+		}
 	}
-
-	return desVolumeClaimTemplateSize, currVolumeClaimTemplateSize, pvcSize
-
+	return result
 }
 
 func isVolumeExpansionEnabled(sdk client.Client, m *v1alpha1.Druid, nodeSpec *v1alpha1.DruidNodeSpec, emitEvent EventEmitter) bool {
@@ -928,7 +1092,10 @@ func makeNodeSpecificUniqueString(m *v1alpha1.Druid, key string) string {
 }
 
 func makeCommonConfigMap(m *v1alpha1.Druid, ls map[string]string) (*v1.ConfigMap, error) {
-	prop := m.Spec.CommonRuntimeProperties
+	prop, err := renderCommonRuntimeProperties(m)
+	if err != nil {
+		return nil, err
+	}
 
 	if m.Spec.Zookeeper != nil {
 		if zm, err := createZookeeperManager(m.Spec.Zookeeper); err != nil {
@@ -954,6 +1121,12 @@ func makeCommonConfigMap(m *v1alpha1.Druid, ls map[string]string) (*v1.ConfigMap
 		}
 	}
 
+	if discoveryProp, err := makeDiscoveryRuntimeProperties(m); err != nil {
+		return nil, err
+	} else if discoveryProp != "" {
+		prop = prop + "\n" + discoveryProp
+	}
+
 	data := map[string]string{
 		"common.runtime.properties": prop,
 	}
@@ -962,6 +1135,10 @@ func makeCommonConfigMap(m *v1alpha1.Druid, ls map[string]string) (*v1.ConfigMap
 		data["metricDimensions.json"] = m.Spec.DimensionsMapPath
 	}
 
+	if err := addExtraFiles(data, m.Spec.ExtraCommonFiles, "extraCommonFiles"); err != nil {
+		return nil, err
+	}
+
 	cfg, err := makeConfigMap(
 		fmt.Sprintf("%s-druid-common-config", m.ObjectMeta.Name),
 		m.Namespace,
@@ -972,15 +1149,68 @@ func makeCommonConfigMap(m *v1alpha1.Druid, ls map[string]string) (*v1.ConfigMap
 
 func makeConfigMapForNodeSpec(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, lm map[string]string, nodeSpecUniqueStr string) (*v1.ConfigMap, error) {
 
+	nodeSpec, err := renderNodeSpecTemplates(nodeSpec, m, nodeSpecUniqueStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// only holds the options and properties not set in the spec
+	memoryProfileJvm, memoryProfileProp, err := makeMemoryProfile(nodeSpec, m)
+	if err != nil {
+		return nil, err
+	}
+
+	// appended to take precedence over the segment cache set in the node properties
+	segmentCacheProp, err := makeSegmentCacheRuntimeProperties(nodeSpec, m)
+	if err != nil {
+		return nil, err
+	}
+	if segmentCacheProp != "" {
+		segmentCacheProp = "\n" + segmentCacheProp
+	}
+
+	// appended to take precedence over the load list of the node properties
+	extensionsProp, err := makeExtensionsRuntimeProperties(nodeSpec, m)
+	if err != nil {
+		return nil, err
+	}
+	if extensionsProp != "" {
+		extensionsProp = "\n" + extensionsProp
+	}
+
+	// appended to take precedence over the runner set in the node properties
+	taskRunnerProp := ""
+	if isKubernetesTaskRunner(nodeSpec) {
+		prop, err := makeTaskRunnerRuntimeProperties(nodeSpec, m)
+		if err != nil {
+			return nil, err
+		}
+		taskRunnerProp = "\n" + prop
+	}
+
 	data := map[string]string{
-		"runtime.properties": fmt.Sprintf("druid.port=%d\n%s", nodeSpec.DruidPort, nodeSpec.RuntimeProperties),
-		"jvm.config":         fmt.Sprintf("%s\n%s", firstNonEmptyStr(nodeSpec.JvmOptions, m.Spec.JvmOptions), nodeSpec.ExtraJvmOptions),
+		"runtime.properties": fmt.Sprintf("druid.port=%d\n%s%s%s%s%s%s", nodeSpec.DruidPort, makeTLSRuntimeProperties(nodeSpec, m), memoryProfileProp, nodeSpec.RuntimeProperties, extensionsProp, segmentCacheProp, taskRunnerProp),
+		"jvm.config":         fmt.Sprintf("%s%s\n%s", memoryProfileJvm, firstNonEmptyStr(nodeSpec.JvmOptions, m.Spec.JvmOptions), nodeSpec.ExtraJvmOptions),
+	}
+	if isKubernetesTaskRunner(nodeSpec) {
+		peonPodTemplate, err := makePeonPodTemplate(nodeSpec, m, nodeSpecUniqueStr)
+		if err != nil {
+			return nil, err
+		}
+		data[peonPodTemplateKey] = peonPodTemplate
+	}
+	log4jconfig, err := makeLog4jConfig(nodeSpec, m)
+	if err != nil {
+		return nil, err
 	}
-	log4jconfig := firstNonEmptyStr(nodeSpec.Log4jConfig, m.Spec.Log4jConfig)
 	if log4jconfig != "" {
 		data["log4j2.xml"] = log4jconfig
 	}
 
+	if err := addExtraFiles(data, nodeSpec.ExtraNodeFiles, "extraNodeFiles"); err != nil {
+		return nil, err
+	}
+
 	return makeConfigMap(
 		fmt.Sprintf("%s-config", nodeSpecUniqueStr),
 		m.Namespace,
@@ -1114,23 +1344,12 @@ func getTopologySpreadConstraints(nodeSpec *v1alpha1.DruidNodeSpec) []v1.Topolog
 func getVolume(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, nodeSpecUniqueStr string) []v1.Volume {
 	volumesHolder := []v1.Volume{
 		{
-			Name: "common-config-volume",
-			VolumeSource: v1.VolumeSource{
-				ConfigMap: &v1.ConfigMapVolumeSource{
-					LocalObjectReference: v1.LocalObjectReference{
-						Name: fmt.Sprintf("%s-druid-common-config", m.ObjectMeta.Name),
-					},
-				}},
+			Name:         "common-config-volume",
+			VolumeSource: makeConfigVolumeSource(fmt.Sprintf("%s-druid-common-config", m.ObjectMeta.Name), m.Spec.ConfigFrom),
 		},
 		{
-			Name: "nodetype-config-volume",
-			VolumeSource: v1.VolumeSource{
-				ConfigMap: &v1.ConfigMapVolumeSource{
-					LocalObjectReference: v1.LocalObjectReference{
-						Name: fmt.Sprintf("%s-config", nodeSpecUniqueStr),
-					},
-				},
-			},
+			Name:         "nodetype-config-volume",
+			VolumeSource: makeConfigVolumeSource(fmt.Sprintf("%s-config", nodeSpecUniqueStr), nodeSpec.ConfigFrom),
 		},
 	}
 	volumesHolder = append(volumesHolder, m.Spec.Volumes...)
@@ -1294,12 +1513,33 @@ func makePodTemplate(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, ls map
 	return v1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:      ls,
-			Annotations: firstNonNilValue(nodeSpec.PodAnnotations, m.Spec.PodAnnotations).(map[string]string),
+			Annotations: makePodAnnotations(nodeSpec, m),
 		},
 		Spec: makePodSpec(nodeSpec, m, nodeSpecUniqueStr, configMapSHA),
 	}
 }
 
+// makePodAnnotations returns the pod annotations of the node, stamped with the restartedAt values if set.
+func makePodAnnotations(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) map[string]string {
+	annotations := firstNonNilValue(nodeSpec.PodAnnotations, m.Spec.PodAnnotations).(map[string]string)
+	if m.Spec.RestartedAt == "" && nodeSpec.RestartedAt == "" {
+		return annotations
+	}
+
+	// copy, annotations map belongs to the CR spec
+	stamped := make(map[string]string, len(annotations)+2)
+	for k, v := range annotations {
+		stamped[k] = v
+	}
+	if m.Spec.RestartedAt != "" {
+		stamped[restartedAtAnnotation] = m.Spec.RestartedAt
+	}
+	if nodeSpec.RestartedAt != "" {
+		stamped[nodeRestartedAtAnnotation] = nodeSpec.RestartedAt
+	}
+	return stamped
+}
+
 // makePodSpec shall create podSpec common to both deployment and statefulset.
 func makePodSpec(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, nodeSpecUniqueStr, configMapSHA string) v1.PodSpec {
 
@@ -1356,6 +1596,18 @@ func makePodSpec(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, nodeSpecUn
 		SecurityContext:               firstNonNilValue(nodeSpec.PodSecurityContext, m.Spec.PodSecurityContext).(*v1.PodSecurityContext),
 		ServiceAccountName:            m.Spec.ServiceAccount,
 	}
+
+	if m.Spec.TLS != nil {
+		addTLSToPodSpec(&spec, m, nodeSpecUniqueStr)
+	}
+	addExtensionsToPodSpec(&spec, nodeSpec, m)
+	addLoggingToPodSpec(&spec, nodeSpec, m)
+	if isKubernetesDiscovery(m) {
+		spec.Containers[0].Env = append(spec.Containers[0].Env, getDiscoveryEnv(m)...)
+	}
+	if isKubernetesTaskRunner(nodeSpec) {
+		spec.ServiceAccountName = getTaskRunnerServiceAccountName(m, nodeSpecUniqueStr)
+	}
 	return spec
 }
 
@@ -1566,6 +1818,33 @@ func makeIngressListEmptyObj() *networkingv1.IngressList {
 	}
 }
 
+func makeServiceAccountListEmptyObj() *v1.ServiceAccountList {
+	return &v1.ServiceAccountList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+	}
+}
+
+func makeRoleListEmptyObj() *rbacv1.RoleList {
+	return &rbacv1.RoleList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Role",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+	}
+}
+
+func makeRoleBindingListEmptyObj() *rbacv1.RoleBindingList {
+	return &rbacv1.RoleBindingList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+	}
+}
+
 func makeConfigMapListEmptyObj() *v1.ConfigMapList {
 	return &v1.ConfigMapList{
 		TypeMeta: metav1.TypeMeta{
@@ -1733,10 +2012,12 @@ func sendEvent(sdk client.Client, drd *v1alpha1.Druid, eventtype, reason, messag
 	}
 }
 
-func verifyDruidSpec(drd *v1alpha1.Druid) error {
+// verifyDruidSpec returns an error listing the invalid parts of the spec, including the config lint errors.
+// The config lint issues are returned so that the warnings are reported without linting the config again.
+func verifyDruidSpec(drd *v1alpha1.Druid) ([]ConfigLintIssue, error) {
 	keyValidationRegex, err := regexp.Compile("[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	errorMsg := ""
@@ -1753,11 +2034,70 @@ func verifyDruidSpec(drd *v1alpha1.Druid) error {
 		errorMsg = fmt.Sprintf("%sStartScript missing from Druid Cluster Spec\n", errorMsg)
 	}
 
-	for key, node := range drd.Spec.Nodes {
+	for name, config := range map[string]string{
+		"coordinatorDynamicConfig": drd.Spec.CoordinatorDynamicConfig,
+		"overlordDynamicConfig":    drd.Spec.OverlordDynamicConfig,
+	} {
+		if config != "" && !json.Valid([]byte(config)) {
+			errorMsg = fmt.Sprintf("%s%s is not valid json\n", errorMsg, name)
+		}
+	}
+
+	if drd.Spec.TLS != nil && drd.Spec.TLS.Provider == tlsProviderCertManager && (drd.Spec.TLS.IssuerRef == nil || drd.Spec.TLS.IssuerRef.Name == "") {
+		errorMsg = fmt.Sprintf("%stls.issuerRef is required with the CertManager provider\n", errorMsg)
+	}
+
+	if err := validateExtraFiles(drd.Spec.ExtraCommonFiles, "extraCommonFiles"); err != nil {
+		errorMsg = fmt.Sprintf("%s%s\n", errorMsg, err.Error())
+	}
+
+	if err := validateExtensions(drd.Spec.Extensions); err != nil {
+		errorMsg = fmt.Sprintf("%s%s\n", errorMsg, err.Error())
+	}
+
+	if !isValidConfigFrom(drd.Spec.ConfigFrom) {
+		errorMsg = fmt.Sprintf("%sconfigFrom sources must reference exactly one of configMapRef or secretRef\n", errorMsg)
+	}
+
+	nodes, err := ResolveNodeSpecs(drd)
+	if err != nil {
+		errorMsg = fmt.Sprintf("%s%s\n", errorMsg, err.Error())
+		nodes = drd.Spec.Nodes
+	}
+
+	// templates failing to render are reported by the config lint
+	for key, node := range nodes {
+		if logging, err := getLoggingSpec(&node, drd); err != nil {
+			errorMsg = fmt.Sprintf("%sNode[%s] %s\n", errorMsg, key, err.Error())
+		} else if logging != nil {
+			if err := validateLoggingSpec(logging); err != nil {
+				errorMsg = fmt.Sprintf("%sNode[%s] %s\n", errorMsg, key, err.Error())
+			}
+		}
+
+		if err := validateExtraFiles(node.ExtraNodeFiles, "extraNodeFiles"); err != nil {
+			errorMsg = fmt.Sprintf("%sNode[%s] %s\n", errorMsg, key, err.Error())
+		}
+
+		if err := validateExtensions(node.Extensions); err != nil {
+			errorMsg = fmt.Sprintf("%sNode[%s] %s\n", errorMsg, key, err.Error())
+		}
+		if err := validateExtensionsLoadList(&node, drd); err != nil {
+			errorMsg = fmt.Sprintf("%sNode[%s] %s\n", errorMsg, key, err.Error())
+		}
+
+		if !isValidConfigFrom(node.ConfigFrom) {
+			errorMsg = fmt.Sprintf("%sNode[%s] configFrom sources must reference exactly one of configMapRef or secretRef\n", errorMsg, key)
+		}
+
 		if node.NodeType == "" {
 			errorMsg = fmt.Sprintf("%sNode[%s] missing NodeType\n", errorMsg, key)
 		}
 
+		if node.DruidPort == 0 {
+			errorMsg = fmt.Sprintf("%sNode[%s] missing DruidPort\n", errorMsg, key)
+		}
+
 		if drd.Spec.Image == "" && node.Image == "" {
 			errorMsg = fmt.Sprintf("%sImage missing from Druid Cluster Spec\n", errorMsg)
 		}
@@ -1770,15 +2110,66 @@ func verifyDruidSpec(drd *v1alpha1.Druid) error {
 			errorMsg = fmt.Sprintf("%sNode[%s] missing NodeConfigMountPath\n", errorMsg, key)
 		}
 
+		if node.BlueGreen != nil && (node.Kind != "Deployment" || (node.NodeType != broker && node.NodeType != router)) {
+			errorMsg = fmt.Sprintf("%sNode[%s] blueGreen is only supported for broker and router nodes of kind Deployment\n", errorMsg, key)
+		}
+
+		if node.TaskAutoscaler != nil && !isTaskAutoscaled(&node) {
+			errorMsg = fmt.Sprintf("%sNode[%s] taskAutoscaler is only supported for middleManager and indexer nodes of kind StatefulSet\n", errorMsg, key)
+		}
+
+		if node.TaskAutoscaler != nil && node.HPAutoScaler != nil {
+			errorMsg = fmt.Sprintf("%sNode[%s] taskAutoscaler and hpAutoscaler are mutually exclusive\n", errorMsg, key)
+		}
+
+		if node.TaskAutoscaler != nil && node.TaskAutoscaler.MinReplicas > node.TaskAutoscaler.MaxReplicas {
+			errorMsg = fmt.Sprintf("%sNode[%s] taskAutoscaler minReplicas is greater than maxReplicas\n", errorMsg, key)
+		}
+
+		if node.AutoExpand != nil && (!isAutoExpanded(&node) || getAutoExpandVolumeClaimTemplateIndex(&node) < 0) {
+			errorMsg = fmt.Sprintf("%sNode[%s] autoExpand is only supported for historical nodes of kind StatefulSet with a volume claim template\n", errorMsg, key)
+		}
+
+		if err := validateSegmentCacheProperties(&node, drd); err != nil {
+			errorMsg = fmt.Sprintf("%sNode[%s] %s\n", errorMsg, key, err.Error())
+		}
+
+		if node.SegmentCache != nil && (node.NodeType != historical || len(getSegmentCacheVolumes(&node, drd, node.SegmentCache.VolumeClaimTemplates)) == 0) {
+			errorMsg = fmt.Sprintf("%sNode[%s] segmentCache is only supported for historical nodes with a mounted volume claim template\n", errorMsg, key)
+		}
+
+		if node.AutoExpand != nil && node.AutoExpand.Step.Sign() <= 0 {
+			errorMsg = fmt.Sprintf("%sNode[%s] autoExpand step must be positive\n", errorMsg, key)
+		}
+
+		if _, ok := getResourceLimit(node.Resources, v1.ResourceMemory); node.MemoryProfile == memoryProfileAuto && !ok {
+			errorMsg = fmt.Sprintf("%sNode[%s] memoryProfile auto requires a memory limit in resources\n", errorMsg, key)
+		}
+
+		if drd.Spec.TLS != nil && drd.Spec.TLS.ValidateHostnames && node.Kind == "Deployment" {
+			errorMsg = fmt.Sprintf("%sNode[%s] tls validateHostnames requires kind StatefulSet, pods of Deployments have no name covered by the certificates\n", errorMsg, key)
+		}
+
+		if node.TaskRunner == taskRunnerKubernetes && node.NodeType != overlord {
+			errorMsg = fmt.Sprintf("%sNode[%s] kubernetes taskRunner is only supported for overlord nodes\n", errorMsg, key)
+		}
+
 		if !keyValidationRegex.MatchString(key) {
 			errorMsg = fmt.Sprintf("%sNode[%s] Key must match k8s resource name regex '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*'", errorMsg, key)
 		}
 	}
 
+	lintIssues := LintDruidConfig(drd)
+	for _, issue := range lintIssues {
+		if issue.Severity == ConfigLintError {
+			errorMsg = fmt.Sprintf("%s%s\n", errorMsg, issue.String())
+		}
+	}
+
 	if errorMsg == "" {
-		return nil
+		return lintIssues, nil
 	} else {
-		return fmt.Errorf(errorMsg)
+		return lintIssues, fmt.Errorf(errorMsg)
 	}
 }
 
@@ -1799,7 +2190,8 @@ func getAllNodeSpecsInDruidPrescribedOrder(m *v1alpha1.Druid) ([]keyAndNodeSpec,
 		router:        make([]keyAndNodeSpec, 0, 1),
 	}
 
-	for key, nodeSpec := range m.Spec.Nodes {
+	nodes := getNodeSpecs(m)
+	for key, nodeSpec := range nodes {
 		nodeSpecs := nodeSpecsByNodeType[nodeSpec.NodeType]
 		if nodeSpecs == nil {
 			return nil, fmt.Errorf("druidSpec[%s:%s] has invalid NodeType[%s]. Deployment aborted", m.Kind, m.Name, nodeSpec.NodeType)
@@ -1808,7 +2200,7 @@ func getAllNodeSpecsInDruidPrescribedOrder(m *v1alpha1.Druid) ([]keyAndNodeSpec,
 		}
 	}
 
-	allNodeSpecs := make([]keyAndNodeSpec, 0, len(m.Spec.Nodes))
+	allNodeSpecs := make([]keyAndNodeSpec, 0, len(nodes))
 
 	allNodeSpecs = append(allNodeSpecs, nodeSpecsByNodeType[historical]...)
 	allNodeSpecs = append(allNodeSpecs, nodeSpecsByNodeType[overlord]...)
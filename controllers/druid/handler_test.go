@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
@@ -58,6 +59,27 @@ func TestDeploymentForBroker(t *testing.T) {
 	assertEquals(expected, actual, t)
 }
 
+func TestMakePodTemplateWithRestartedAt(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+
+	nodeSpecUniqueStr := makeNodeSpecificUniqueString(clusterSpec, "brokers")
+	nodeSpec := clusterSpec.Spec.Nodes["brokers"]
+	ls := makeLabelsForNodeSpec(&nodeSpec, clusterSpec, clusterSpec.Name, nodeSpecUniqueStr)
+
+	clusterSpec.Spec.RestartedAt = "2022-01-01T00:00:00Z"
+	nodeSpec.RestartedAt = "2022-01-02T00:00:00Z"
+
+	actual := makePodTemplate(&nodeSpec, clusterSpec, ls, nodeSpecUniqueStr, "blah")
+
+	if actual.Annotations[restartedAtAnnotation] != clusterSpec.Spec.RestartedAt || actual.Annotations[nodeRestartedAtAnnotation] != nodeSpec.RestartedAt {
+		t.Errorf("restartedAt not stamped in pod template annotations [%v]", actual.Annotations)
+	}
+
+	if _, ok := clusterSpec.Spec.PodAnnotations[restartedAtAnnotation]; ok {
+		t.Errorf("pod annotations of the CR spec must not be mutated")
+	}
+}
+
 func TestMakePodDisruptionBudgetForBroker(t *testing.T) {
 	clusterSpec := readSampleDruidClusterSpec(t)
 
@@ -130,6 +152,21 @@ func TestMakeBrokerConfigMap(t *testing.T) {
 	assertEquals(expected, actual, t)
 }
 
+func TestVerifyDruidSpecDynamicConfigs(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+
+	clusterSpec.Spec.CoordinatorDynamicConfig = `{"maxSegmentsToMove":100}`
+	clusterSpec.Spec.OverlordDynamicConfig = `{"selectStrategy":{"type":"equalDistribution"}}`
+	if _, err := verifyDruidSpec(clusterSpec); err != nil && strings.Contains(err.Error(), "DynamicConfig") {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	clusterSpec.Spec.OverlordDynamicConfig = `{"selectStrategy":`
+	if _, err := verifyDruidSpec(clusterSpec); err == nil || !strings.Contains(err.Error(), "overlordDynamicConfig is not valid json") {
+		t.Errorf("invalid overlordDynamicConfig json must be rejected")
+	}
+}
+
 func readSampleDruidClusterSpec(t *testing.T) *v1alpha1.Druid {
 	return readDruidClusterSpecFromFile(t, "testdata/druid-test-cr.yaml")
 }
@@ -0,0 +1,260 @@
+package druid
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultLogLevel        = "info"
+	defaultLogPattern      = "%d{ISO8601} %p [%t] %c - %m%n"
+	defaultLogDirectory    = "/druid/logs"
+	defaultLogMaxFileSize  = "100 MB"
+	defaultLogMaxFiles     = 10
+	logLayoutJSON          = "json"
+	requestLoggerName      = "org.apache.druid.server.log.LoggingRequestLogger"
+	emitterLoggerName      = "org.apache.druid.java.util.emitter.core.LoggingEmitter"
+	defaultRequestLogFile  = "request.log"
+	defaultEmitterLogFile  = "emitter.log"
+	defaultLogFile         = "druid.log"
+	logFileRolloverPattern = "%d{yyyy-MM-dd}-%i.log.gz"
+	logsVolumeName         = "druid-logs"
+)
+
+var logLevels = []string{"trace", "debug", "info", "warn", "error", "fatal", "off"}
+
+type log4jAppender struct {
+	Name     string
+	FileName string
+}
+
+type log4jLogger struct {
+	Name     string
+	Level    string
+	Appender string
+}
+
+type log4jConfig struct {
+	Layout      string
+	RootLevel   string
+	RollingFile *v1alpha1.RollingFileSpec
+	Appenders   []log4jAppender
+	Loggers     []log4jLogger
+}
+
+var log4jTemplate = template.Must(template.New("log4j2.xml").Funcs(template.FuncMap{"xml": escapeXML}).Parse(
+	`<?xml version="1.0" encoding="UTF-8" ?>
+<Configuration status="WARN">
+  <Appenders>
+    <Console name="Console" target="SYSTEM_OUT">
+      {{ .Layout }}
+    </Console>
+{{- range .Appenders }}
+{{- if $.RollingFile }}
+    <RollingFile name="{{ .Name }}" fileName="{{ xml $.RollingFile.Directory }}/{{ xml .FileName }}" filePattern="{{ xml $.RollingFile.Directory }}/{{ xml .FileName }}-` + logFileRolloverPattern + `">
+      {{ $.Layout }}
+      <Policies>
+        <SizeBasedTriggeringPolicy size="{{ xml $.RollingFile.MaxFileSize }}"/>
+      </Policies>
+      <DefaultRolloverStrategy max="{{ $.RollingFile.MaxFiles }}"/>
+    </RollingFile>
+{{- else }}
+    <Console name="{{ .Name }}" target="SYSTEM_OUT">
+      {{ $.Layout }}
+    </Console>
+{{- end }}
+{{- end }}
+  </Appenders>
+  <Loggers>
+    <Root level="{{ .RootLevel }}">
+      <AppenderRef ref="Console"/>
+{{- if .RollingFile }}
+      <AppenderRef ref="File"/>
+{{- end }}
+    </Root>
+{{- range .Loggers }}
+{{- if .Appender }}
+    <Logger name="{{ xml .Name }}" level="{{ .Level }}" additivity="false">
+      <AppenderRef ref="{{ .Appender }}"/>
+    </Logger>
+{{- else }}
+    <Logger name="{{ xml .Name }}" level="{{ .Level }}"/>
+{{- end }}
+{{- end }}
+  </Loggers>
+</Configuration>
+`))
+
+// getLoggingSpec returns the logging spec of the node spec merged into the one of the cluster, nil if both are nil.
+func getLoggingSpec(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) (*v1alpha1.LoggingSpec, error) {
+	if nodeSpec.Logging == nil || m.Spec.Logging == nil {
+		return firstNonNilValue(nodeSpec.Logging, m.Spec.Logging).(*v1alpha1.LoggingSpec), nil
+	}
+
+	base, err := toJSONMap(m.Spec.Logging)
+	if err != nil {
+		return nil, err
+	}
+	override, err := toJSONMap(nodeSpec.Logging)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := json.Marshal(mergeJSONMaps(base, override))
+	if err != nil {
+		return nil, err
+	}
+	merged := &v1alpha1.LoggingSpec{}
+	if err := json.Unmarshal(bytes, merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// makeLog4jConfig returns the log4j2.xml of the node spec, its log4j config taking precedence over the generated one.
+// The node spec is expected to be rendered, its log4j config holding the one of the cluster if not overridden by
+// its logging spec.
+func makeLog4jConfig(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) (string, error) {
+	if nodeSpec.Log4jConfig != "" {
+		return nodeSpec.Log4jConfig, nil
+	}
+	logging, err := getLoggingSpec(nodeSpec, m)
+	if err != nil || logging == nil {
+		return "", err
+	}
+	if err := validateLoggingSpec(logging); err != nil {
+		return "", err
+	}
+
+	config := log4jConfig{
+		Layout:    fmt.Sprintf(`<PatternLayout pattern="%s"/>`, escapeXML(firstNonEmptyStr(logging.Pattern, defaultLogPattern))),
+		RootLevel: strings.ToLower(firstNonEmptyStr(logging.RootLevel, defaultLogLevel)),
+	}
+	if logging.Layout == logLayoutJSON {
+		config.Layout = `<JsonLayout compact="true" eventEol="true" properties="true" stacktraceAsString="true"/>`
+	}
+	if logging.RollingFile != nil {
+		config.RollingFile = &v1alpha1.RollingFileSpec{
+			Directory:   getRollingFileDirectory(logging.RollingFile),
+			MaxFileSize: firstNonEmptyStr(logging.RollingFile.MaxFileSize, defaultLogMaxFileSize),
+			MaxFiles:    logging.RollingFile.MaxFiles,
+		}
+		if config.RollingFile.MaxFiles == 0 {
+			config.RollingFile.MaxFiles = defaultLogMaxFiles
+		}
+		config.Appenders = append(config.Appenders, log4jAppender{Name: "File", FileName: defaultLogFile})
+	}
+
+	names := make([]string, 0, len(logging.Loggers))
+	for name := range logging.Loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		config.Loggers = append(config.Loggers, log4jLogger{Name: name, Level: strings.ToLower(logging.Loggers[name])})
+	}
+
+	for _, appender := range []struct {
+		name, logger, fileName string
+		spec                   *v1alpha1.LogAppenderSpec
+	}{
+		{"RequestLog", requestLoggerName, defaultRequestLogFile, logging.RequestLog},
+		{"Emitter", emitterLoggerName, defaultEmitterLogFile, logging.Emitter},
+	} {
+		if appender.spec == nil {
+			continue
+		}
+		config.Appenders = append(config.Appenders, log4jAppender{Name: appender.name, FileName: firstNonEmptyStr(appender.spec.FileName, appender.fileName)})
+		config.Loggers = append(config.Loggers, log4jLogger{Name: appender.logger, Level: strings.ToLower(firstNonEmptyStr(appender.spec.Level, defaultLogLevel)), Appender: appender.name})
+	}
+
+	var buf bytes.Buffer
+	if err := log4jTemplate.Execute(&buf, config); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// validateLoggingSpec returns an error if a level is unknown, a logger is also the one of an appender, or the
+// directory of the rolling files is not an absolute path below the root.
+func validateLoggingSpec(logging *v1alpha1.LoggingSpec) error {
+	levels := map[string]string{"rootLevel": logging.RootLevel}
+	for name, level := range logging.Loggers {
+		levels[fmt.Sprintf("logger [%s]", name)] = level
+	}
+	for _, appender := range []struct {
+		field, logger string
+		spec          *v1alpha1.LogAppenderSpec
+	}{
+		{"requestLog", requestLoggerName, logging.RequestLog},
+		{"emitter", emitterLoggerName, logging.Emitter},
+	} {
+		if appender.spec == nil {
+			continue
+		}
+		if _, ok := logging.Loggers[appender.logger]; ok {
+			return fmt.Errorf("logger [%s] is configured by %s", appender.logger, appender.field)
+		}
+		levels[appender.field] = appender.spec.Level
+	}
+
+	for name, level := range levels {
+		if level != "" && !ContainsString(logLevels, strings.ToLower(level)) {
+			return fmt.Errorf("invalid level [%s] of %s, expected one of %v", level, name, logLevels)
+		}
+	}
+
+	if logging.RollingFile != nil {
+		if dir := getRollingFileDirectory(logging.RollingFile); !path.IsAbs(dir) || path.Clean(dir) == "/" {
+			return fmt.Errorf("rollingFile directory [%s] must be an absolute path other than /", logging.RollingFile.Directory)
+		}
+	}
+	return nil
+}
+
+func getRollingFileDirectory(rollingFile *v1alpha1.RollingFileSpec) string {
+	return strings.TrimSuffix(firstNonEmptyStr(rollingFile.Directory, defaultLogDirectory), "/")
+}
+
+// addLoggingToPodSpec mounts an emptyDir at the directory of the rolling files of the generated log4j config, unless
+// a volume is already mounted there.
+func addLoggingToPodSpec(spec *v1.PodSpec, nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) {
+	log4jConfig := nodeSpec.Log4jConfig
+	if nodeSpec.Logging == nil {
+		log4jConfig = firstNonEmptyStr(nodeSpec.Log4jConfig, m.Spec.Log4jConfig)
+	}
+	if log4jConfig != "" {
+		return
+	}
+	logging, err := getLoggingSpec(nodeSpec, m)
+	if err != nil || logging == nil || logging.RollingFile == nil {
+		return
+	}
+
+	dir := getRollingFileDirectory(logging.RollingFile)
+	druid := &spec.Containers[0]
+	for _, mount := range druid.VolumeMounts {
+		if path.Clean(mount.MountPath) == path.Clean(dir) {
+			return
+		}
+	}
+	spec.Volumes = append(spec.Volumes, v1.Volume{
+		Name:         logsVolumeName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+	})
+	druid.VolumeMounts = append(druid.VolumeMounts, v1.VolumeMount{Name: logsVolumeName, MountPath: dir})
+}
+
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
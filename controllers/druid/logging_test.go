@@ -0,0 +1,193 @@
+package druid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestMakeLog4jConfig(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	clusterSpec.Spec.Log4jConfig = ""
+	clusterSpec.Spec.Logging = &v1alpha1.LoggingSpec{
+		RootLevel:   "warn",
+		Loggers:     map[string]string{"org.apache.druid.server.coordinator": "DEBUG"},
+		RollingFile: &v1alpha1.RollingFileSpec{Directory: "/druid/logs/"},
+		RequestLog:  &v1alpha1.LogAppenderSpec{},
+	}
+	brokers := clusterSpec.Spec.Nodes["brokers"]
+	brokers.Log4jConfig = ""
+	brokers.Logging = &v1alpha1.LoggingSpec{
+		Layout:  "json",
+		Loggers: map[string]string{"org.apache.druid.query": "debug"},
+		Emitter: &v1alpha1.LogAppenderSpec{Level: "info", FileName: "metrics.log"},
+	}
+
+	config, err := makeLog4jConfig(&brokers, clusterSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8" ?>
+<Configuration status="WARN">
+  <Appenders>
+    <Console name="Console" target="SYSTEM_OUT">
+      <JsonLayout compact="true" eventEol="true" properties="true" stacktraceAsString="true"/>
+    </Console>
+    <RollingFile name="File" fileName="/druid/logs/druid.log" filePattern="/druid/logs/druid.log-%d{yyyy-MM-dd}-%i.log.gz">
+      <JsonLayout compact="true" eventEol="true" properties="true" stacktraceAsString="true"/>
+      <Policies>
+        <SizeBasedTriggeringPolicy size="100 MB"/>
+      </Policies>
+      <DefaultRolloverStrategy max="10"/>
+    </RollingFile>
+    <RollingFile name="RequestLog" fileName="/druid/logs/request.log" filePattern="/druid/logs/request.log-%d{yyyy-MM-dd}-%i.log.gz">
+      <JsonLayout compact="true" eventEol="true" properties="true" stacktraceAsString="true"/>
+      <Policies>
+        <SizeBasedTriggeringPolicy size="100 MB"/>
+      </Policies>
+      <DefaultRolloverStrategy max="10"/>
+    </RollingFile>
+    <RollingFile name="Emitter" fileName="/druid/logs/metrics.log" filePattern="/druid/logs/metrics.log-%d{yyyy-MM-dd}-%i.log.gz">
+      <JsonLayout compact="true" eventEol="true" properties="true" stacktraceAsString="true"/>
+      <Policies>
+        <SizeBasedTriggeringPolicy size="100 MB"/>
+      </Policies>
+      <DefaultRolloverStrategy max="10"/>
+    </RollingFile>
+  </Appenders>
+  <Loggers>
+    <Root level="warn">
+      <AppenderRef ref="Console"/>
+      <AppenderRef ref="File"/>
+    </Root>
+    <Logger name="org.apache.druid.query" level="debug"/>
+    <Logger name="org.apache.druid.server.coordinator" level="debug"/>
+    <Logger name="org.apache.druid.server.log.LoggingRequestLogger" level="info" additivity="false">
+      <AppenderRef ref="RequestLog"/>
+    </Logger>
+    <Logger name="org.apache.druid.java.util.emitter.core.LoggingEmitter" level="info" additivity="false">
+      <AppenderRef ref="Emitter"/>
+    </Logger>
+  </Loggers>
+</Configuration>
+`
+	if config != expected {
+		t.Errorf("expected [%s], got [%s]", expected, config)
+	}
+
+	// raw log4j config of the cluster is overridden by the logging of the node spec
+	clusterSpec.Spec.Log4jConfig = "<Configuration/>"
+	rendered, err := renderNodeSpecTemplates(&brokers, clusterSpec, "druid-druid-test-brokers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config, _ := makeLog4jConfig(rendered, clusterSpec); config != expected {
+		t.Errorf("logging of the node spec must take precedence over the cluster log4j config, got [%s]", config)
+	}
+
+	// raw log4j config of the node spec takes precedence
+	brokers.Log4jConfig = "<Configuration/>"
+	if config, _ := makeLog4jConfig(&brokers, clusterSpec); config != "<Configuration/>" {
+		t.Errorf("log4j config of the node spec must take precedence, got [%s]", config)
+	}
+
+	brokers.Log4jConfig = ""
+	brokers.Logging.Loggers["org.apache.druid.query"] = "verbose"
+	clusterSpec.Spec.Nodes["brokers"] = brokers
+	if _, err := verifyDruidSpec(clusterSpec); err == nil || !strings.Contains(err.Error(), "Node[brokers] invalid level [verbose] of logger [org.apache.druid.query]") {
+		t.Errorf("unknown logger level must be rejected, got [%v]", err)
+	}
+}
+
+func TestMakeLog4jConfigPattern(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	clusterSpec.Spec.Log4jConfig = ""
+	clusterSpec.Spec.Logging = &v1alpha1.LoggingSpec{Pattern: "%d <%p> %m%n", RequestLog: &v1alpha1.LogAppenderSpec{Level: "debug"}}
+	historicals := clusterSpec.Spec.Nodes["historicals"]
+	historicals.Log4jConfig = ""
+
+	config, err := makeLog4jConfig(&historicals, clusterSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<PatternLayout pattern="%d &lt;%p&gt; %m%n"/>`,
+		`<Console name="RequestLog" target="SYSTEM_OUT">`,
+		`<Root level="info">`,
+		`<Logger name="org.apache.druid.server.log.LoggingRequestLogger" level="debug" additivity="false">`,
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("expected [%s] in [%s]", expected, config)
+		}
+	}
+	if strings.Contains(config, "RollingFile") || strings.Contains(config, `ref="File"`) {
+		t.Errorf("rolling files must not be generated without rollingFile, got [%s]", config)
+	}
+}
+
+func TestValidateLoggingSpec(t *testing.T) {
+	for _, tc := range []struct {
+		logging  v1alpha1.LoggingSpec
+		expected string
+	}{
+		{v1alpha1.LoggingSpec{RootLevel: "WARN", RequestLog: &v1alpha1.LogAppenderSpec{Level: "Debug"}}, ""},
+		{v1alpha1.LoggingSpec{RootLevel: "verbose"}, "invalid level [verbose] of rootLevel"},
+		{v1alpha1.LoggingSpec{Emitter: &v1alpha1.LogAppenderSpec{Level: "verbose"}}, "invalid level [verbose] of emitter"},
+		{v1alpha1.LoggingSpec{RequestLog: &v1alpha1.LogAppenderSpec{Level: "verbose"}}, "invalid level [verbose] of requestLog"},
+		{v1alpha1.LoggingSpec{Loggers: map[string]string{emitterLoggerName: "debug"}}, ""},
+		{v1alpha1.LoggingSpec{Loggers: map[string]string{emitterLoggerName: "debug"}, Emitter: &v1alpha1.LogAppenderSpec{}}, "is configured by emitter"},
+		{v1alpha1.LoggingSpec{Loggers: map[string]string{requestLoggerName: "debug"}, RequestLog: &v1alpha1.LogAppenderSpec{}}, "is configured by requestLog"},
+		{v1alpha1.LoggingSpec{RollingFile: &v1alpha1.RollingFileSpec{}}, ""},
+		{v1alpha1.LoggingSpec{RollingFile: &v1alpha1.RollingFileSpec{Directory: "logs"}}, "must be an absolute path"},
+		{v1alpha1.LoggingSpec{RollingFile: &v1alpha1.RollingFileSpec{Directory: "/"}}, "must be an absolute path"},
+	} {
+		err := validateLoggingSpec(&tc.logging)
+		if (tc.expected == "" && err != nil) || (tc.expected != "" && (err == nil || !strings.Contains(err.Error(), tc.expected))) {
+			t.Errorf("expected error [%s] for %+v, got [%v]", tc.expected, tc.logging, err)
+		}
+	}
+
+	config, err := makeLog4jConfig(&v1alpha1.DruidNodeSpec{}, &v1alpha1.Druid{Spec: v1alpha1.DruidSpec{
+		Logging: &v1alpha1.LoggingSpec{RootLevel: "WARN", Emitter: &v1alpha1.LogAppenderSpec{Level: "DEBUG"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(config, `<Root level="warn">`) || !strings.Contains(config, `level="debug" additivity="false"`) {
+		t.Errorf("levels must be lowercased, got [%s]", config)
+	}
+}
+
+func TestAddLoggingToPodSpec(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	clusterSpec.Spec.Log4jConfig = ""
+	clusterSpec.Spec.Logging = &v1alpha1.LoggingSpec{RollingFile: &v1alpha1.RollingFileSpec{Directory: "/var/log/druid/"}}
+	brokers := clusterSpec.Spec.Nodes["brokers"]
+	brokers.Log4jConfig = ""
+	nodeSpecUniqueStr := makeNodeSpecificUniqueString(clusterSpec, "brokers")
+
+	spec := makePodSpec(&brokers, clusterSpec, nodeSpecUniqueStr, "")
+	mounted := false
+	for _, mount := range spec.Containers[0].VolumeMounts {
+		mounted = mounted || (mount.Name == logsVolumeName && mount.MountPath == "/var/log/druid")
+	}
+	if !mounted {
+		t.Errorf("expected an emptyDir mounted at the rolling file directory, got %v", spec.Containers[0].VolumeMounts)
+	}
+
+	brokers.VolumeMounts = append(brokers.VolumeMounts, v1.VolumeMount{Name: "logs", MountPath: "/var/log/druid"})
+	for _, volume := range makePodSpec(&brokers, clusterSpec, nodeSpecUniqueStr, "").Volumes {
+		if volume.Name == logsVolumeName {
+			t.Errorf("volume mounted by the node spec at the rolling file directory must be kept")
+		}
+	}
+
+	brokers.Log4jConfig = "<Configuration/>"
+	for _, volume := range makePodSpec(&brokers, clusterSpec, nodeSpecUniqueStr, "").Volumes {
+		if volume.Name == logsVolumeName {
+			t.Errorf("no volume must be added for a raw log4j config")
+		}
+	}
+}
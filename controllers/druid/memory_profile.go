@@ -0,0 +1,159 @@
+package druid
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	memoryProfileAuto              = "auto"
+	processingNumThreadsProperty   = "druid.processing.numThreads"
+	processingMergeBuffersProperty = "druid.processing.numMergeBuffers"
+	processingBufferSizeProperty   = "druid.processing.buffer.sizeBytes"
+	maxDirectMemoryOption          = "-XX:MaxDirectMemorySize="
+	minHeapOption                  = "-Xms"
+	maxHeapOption                  = "-Xmx"
+	// druid does not use processing buffers larger than 1GiB
+	maxProcessingBufferSize = 1 << 30
+	minHeapSize             = 64 << 20
+)
+
+var jvmBytesRegex = regexp.MustCompile(`^([0-9]+)([kKmMgGtT]?)$`)
+
+// memoryProfile is the share of the container memory given to the heap and the direct memory of a node type,
+// following the basic cluster tuning guide of druid. What is left is used by the page cache and the jvm itself.
+type memoryProfile struct {
+	heapPercent   int64
+	directPercent int64
+	maxHeap       int64
+	// nodes processing queries get processing threads and buffers
+	processing bool
+}
+
+var memoryProfiles = map[string]memoryProfile{
+	historical:    {heapPercent: 25, directPercent: 35, processing: true},
+	broker:        {heapPercent: 50, directPercent: 30, processing: true},
+	indexer:       {heapPercent: 40, directPercent: 30, processing: true},
+	coordinator:   {heapPercent: 75},
+	overlord:      {heapPercent: 75},
+	router:        {heapPercent: 75, maxHeap: 1 << 30},
+	middleManager: {heapPercent: 75, maxHeap: 256 << 20},
+}
+
+// getResourceLimit returns the limit of the resource, or its request if no limit is set.
+func getResourceLimit(resources v1.ResourceRequirements, name v1.ResourceName) (resource.Quantity, bool) {
+	if q, ok := resources.Limits[name]; ok && !q.IsZero() {
+		return q, true
+	}
+	q, ok := resources.Requests[name]
+	return q, ok && !q.IsZero()
+}
+
+// getJvmOption returns the value of the last jvm option starting with prefix.
+func getJvmOption(options, prefix string) (string, bool) {
+	value, found := "", false
+	for _, option := range strings.Fields(options) {
+		if strings.HasPrefix(option, prefix) {
+			value, found = strings.TrimPrefix(option, prefix), true
+		}
+	}
+	return value, found
+}
+
+// parseJvmBytes parses a jvm memory size such as 512m or 4g, units being binary.
+func parseJvmBytes(value string) (int64, error) {
+	match := jvmBytesRegex.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid jvm size [%s]", value)
+	}
+	size, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	shift := map[string]uint{"": 0, "k": 10, "m": 20, "g": 30, "t": 40}[strings.ToLower(match[2])]
+	return size << shift, nil
+}
+
+// makeMemoryProfile returns the jvm options and runtime properties of the node sized from the cpu and memory of its
+// resources. Only the options and properties not set in the spec, for the node or in the common properties, are
+// returned, and the ones set are taken into account to size the others.
+func makeMemoryProfile(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) (string, string, error) {
+	profile, ok := memoryProfiles[nodeSpec.NodeType]
+	if nodeSpec.MemoryProfile != memoryProfileAuto || !ok {
+		return "", "", nil
+	}
+	memory, ok := getResourceLimit(nodeSpec.Resources, v1.ResourceMemory)
+	if !ok {
+		return "", "", fmt.Errorf("memoryProfile %s requires a memory limit", memoryProfileAuto)
+	}
+	jvmOptions := fmt.Sprintf("%s\n%s", firstNonEmptyStr(nodeSpec.JvmOptions, m.Spec.JvmOptions), nodeSpec.ExtraJvmOptions)
+	properties := fmt.Sprintf("%s\n%s", m.Spec.CommonRuntimeProperties, nodeSpec.RuntimeProperties)
+
+	options, props := "", ""
+	_, minHeapSet := getJvmOption(jvmOptions, minHeapOption)
+	_, maxHeapSet := getJvmOption(jvmOptions, maxHeapOption)
+	if !minHeapSet && !maxHeapSet {
+		heap := memory.Value() / 100 * profile.heapPercent
+		if profile.maxHeap > 0 && heap > profile.maxHeap {
+			heap = profile.maxHeap
+		}
+		if heap < minHeapSize {
+			heap = minHeapSize
+		}
+		options = fmt.Sprintf("%s%s%dm\n%s%dm\n", options, minHeapOption, heap>>20, maxHeapOption, heap>>20)
+	}
+	if !profile.processing {
+		return options, props, nil
+	}
+
+	var cores int64 = 1
+	if cpu, ok := getResourceLimit(nodeSpec.Resources, v1.ResourceCPU); ok && cpu.MilliValue() > 1000 {
+		cores = cpu.MilliValue() / 1000
+	}
+
+	var threads, mergeBuffers, bufferSize, direct int64
+	value, threadsSet := getPropertyValue(properties, processingNumThreadsProperty)
+	if threads, _ = strconv.ParseInt(value, 10, 64); !threadsSet || threads <= 0 {
+		threads = cores - 1
+		if threads < 1 {
+			threads = 1
+		}
+		props = fmt.Sprintf("%s%s=%d\n", props, processingNumThreadsProperty, threads)
+	}
+	value, mergeBuffersSet := getPropertyValue(properties, processingMergeBuffersProperty)
+	if mergeBuffers, _ = strconv.ParseInt(value, 10, 64); !mergeBuffersSet || mergeBuffers < 0 {
+		mergeBuffers = threads / 4
+		if mergeBuffers < 2 {
+			mergeBuffers = 2
+		}
+		props = fmt.Sprintf("%s%s=%d\n", props, processingMergeBuffersProperty, mergeBuffers)
+	}
+	// processing buffers allocated in direct memory
+	buffers := threads + mergeBuffers + 1
+
+	value, directSet := getJvmOption(jvmOptions, maxDirectMemoryOption)
+	if direct, _ = parseJvmBytes(value); !directSet || direct <= 0 {
+		directSet = false
+		direct = memory.Value() / 100 * profile.directPercent
+	}
+	value, bufferSizeSet := getPropertyValue(properties, processingBufferSizeProperty)
+	if bufferSize, _ = parseDruidBytes(value); !bufferSizeSet || bufferSize <= 0 {
+		bufferSize = direct / buffers
+		if bufferSize > maxProcessingBufferSize {
+			bufferSize = maxProcessingBufferSize
+		}
+		props = fmt.Sprintf("%s%s=%d\n", props, processingBufferSizeProperty, bufferSize)
+	}
+	if !directSet {
+		// rounded up to the megabyte to fit the buffers
+		direct = (bufferSize*buffers + 1<<20 - 1) >> 20
+		options = fmt.Sprintf("%s%s%dm\n", options, maxDirectMemoryOption, direct)
+	}
+	return options, props, nil
+}
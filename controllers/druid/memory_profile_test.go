@@ -0,0 +1,60 @@
+package druid
+
+import (
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestMakeMemoryProfile(t *testing.T) {
+	m := &v1alpha1.Druid{Spec: v1alpha1.DruidSpec{JvmOptions: "-server"}}
+	resources := v1.ResourceRequirements{
+		Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("8"), v1.ResourceMemory: resource.MustParse("16Gi")},
+	}
+
+	for _, test := range []struct {
+		name          string
+		nodeSpec      v1alpha1.DruidNodeSpec
+		expectedJvm   string
+		expectedProps string
+	}{
+		{
+			name:          "broker",
+			nodeSpec:      v1alpha1.DruidNodeSpec{NodeType: broker, Resources: resources},
+			expectedJvm:   "-Xms8191m\n-Xmx8191m\n-XX:MaxDirectMemorySize=4916m\n",
+			expectedProps: "druid.processing.numThreads=7\ndruid.processing.numMergeBuffers=2\ndruid.processing.buffer.sizeBytes=515396073\n",
+		},
+		{
+			name: "historical with explicit values",
+			nodeSpec: v1alpha1.DruidNodeSpec{NodeType: historical, Resources: resources,
+				RuntimeProperties: "druid.processing.numThreads=3", ExtraJvmOptions: "-XX:MaxDirectMemorySize=4g"},
+			expectedJvm:   "-Xms4095m\n-Xmx4095m\n",
+			expectedProps: "druid.processing.numMergeBuffers=2\ndruid.processing.buffer.sizeBytes=715827882\n",
+		},
+		{
+			name:        "middleManager",
+			nodeSpec:    v1alpha1.DruidNodeSpec{NodeType: middleManager, Resources: resources},
+			expectedJvm: "-Xms256m\n-Xmx256m\n",
+		},
+		{
+			name:     "explicit heap",
+			nodeSpec: v1alpha1.DruidNodeSpec{NodeType: coordinator, Resources: resources, JvmOptions: "-Xmx1g"},
+		},
+	} {
+		test.nodeSpec.MemoryProfile = memoryProfileAuto
+		jvm, props, err := makeMemoryProfile(&test.nodeSpec, m)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if jvm != test.expectedJvm || props != test.expectedProps {
+			t.Errorf("%s: expected [%s] [%s], got [%s] [%s]", test.name, test.expectedJvm, test.expectedProps, jvm, props)
+		}
+	}
+
+	nodeSpec := v1alpha1.DruidNodeSpec{NodeType: broker, MemoryProfile: memoryProfileAuto}
+	if _, _, err := makeMemoryProfile(&nodeSpec, m); err == nil {
+		t.Errorf("memoryProfile without memory limit must be rejected")
+	}
+}
@@ -0,0 +1,187 @@
+package druid
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+const runtimePropertiesField = "runtime.properties"
+
+// ResolveNodeSpecs returns the node specs of the cluster with the ones extending another node spec merged with it.
+// Maps are merged, lists are replaced and runtime properties are merged by key. Fields of the extending node spec
+// left to their zero value are inherited, except restartedAt.
+func ResolveNodeSpecs(m *v1alpha1.Druid) (map[string]v1alpha1.DruidNodeSpec, error) {
+	resolved := make(map[string]v1alpha1.DruidNodeSpec, len(m.Spec.Nodes))
+	for key := range m.Spec.Nodes {
+		if _, err := resolveNodeSpec(m, key, resolved, []string{}); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// getNodeSpecs returns the resolved node specs of the cluster, or the declared ones if they cannot be resolved,
+// such specs being rejected by verifyDruidSpec.
+func getNodeSpecs(m *v1alpha1.Druid) map[string]v1alpha1.DruidNodeSpec {
+	nodes, err := ResolveNodeSpecs(m)
+	if err != nil {
+		return m.Spec.Nodes
+	}
+	return nodes
+}
+
+func resolveNodeSpec(m *v1alpha1.Druid, key string, resolved map[string]v1alpha1.DruidNodeSpec, path []string) (v1alpha1.DruidNodeSpec, error) {
+	if nodeSpec, ok := resolved[key]; ok {
+		return nodeSpec, nil
+	}
+	nodeSpec, ok := m.Spec.Nodes[key]
+	if !ok {
+		return nodeSpec, fmt.Errorf("Node[%s] extends unknown node spec [%s]", path[len(path)-1], key)
+	}
+	if ContainsString(path, key) {
+		return nodeSpec, fmt.Errorf("Node[%s] has an inheritance cycle [%s -> %s]", key, strings.Join(path, " -> "), key)
+	}
+	if nodeSpec.Extends == "" {
+		resolved[key] = nodeSpec
+		return nodeSpec, nil
+	}
+
+	parent, err := resolveNodeSpec(m, nodeSpec.Extends, resolved, append(path, key))
+	if err != nil {
+		return nodeSpec, err
+	}
+	merged, err := mergeNodeSpecs(&parent, &nodeSpec)
+	if err != nil {
+		return nodeSpec, fmt.Errorf("Node[%s] cannot extend [%s] due to [%s]", key, nodeSpec.Extends, err.Error())
+	}
+	resolved[key] = *merged
+	return *merged, nil
+}
+
+// mergeNodeSpecs returns the child node spec merged into the parent one.
+func mergeNodeSpecs(parent, child *v1alpha1.DruidNodeSpec) (*v1alpha1.DruidNodeSpec, error) {
+	parentFields, err := toJSONMap(parent)
+	if err != nil {
+		return nil, err
+	}
+	childFields, err := toJSONMap(child)
+	if err != nil {
+		return nil, err
+	}
+	// fields without omitempty are always marshalled, they are only set if not zero
+	for name, zero := range getRequiredNodeSpecFields() {
+		if reflect.DeepEqual(childFields[name], zero) {
+			delete(childFields, name)
+		}
+	}
+	delete(childFields, "extends")
+	// restarts of a node spec do not restart the ones extending it
+	delete(parentFields, "restartedAt")
+
+	merged := mergeJSONMaps(parentFields, childFields)
+	if p, ok := childFields[runtimePropertiesField].(string); ok {
+		merged[runtimePropertiesField] = mergeProperties(parent.RuntimeProperties, p)
+	}
+
+	bytes, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	nodeSpec := &v1alpha1.DruidNodeSpec{}
+	if err := json.Unmarshal(bytes, nodeSpec); err != nil {
+		return nil, err
+	}
+	return nodeSpec, nil
+}
+
+// getRequiredNodeSpecFields returns the json names of the node spec fields without omitempty, with the json value
+// of their zero value.
+func getRequiredNodeSpecFields() map[string]interface{} {
+	fields := map[string]interface{}{}
+	zero, _ := toJSONMap(&v1alpha1.DruidNodeSpec{})
+	t := reflect.TypeOf(v1alpha1.DruidNodeSpec{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")
+		if tag[0] != "" && tag[0] != "-" && !ContainsString(tag[1:], "omitempty") {
+			fields[tag[0]] = zero[tag[0]]
+		}
+	}
+	return fields
+}
+
+func toJSONMap(v interface{}) (map[string]interface{}, error) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeJSONMaps returns the override values merged into base, nested objects are merged and other values replaced.
+func mergeJSONMaps(base, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		baseMap, baseIsMap := merged[k].(map[string]interface{})
+		overrideMap, overrideIsMap := v.(map[string]interface{})
+		if baseIsMap && overrideIsMap {
+			merged[k] = mergeJSONMaps(baseMap, overrideMap)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// mergeProperties returns the base properties with the values of the keys set in override replaced in place,
+// followed by the lines of override with new keys.
+func mergeProperties(base, override string) string {
+	overrides := map[string]string{}
+	added := []string{}
+	for _, line := range strings.Split(override, "\n") {
+		if key, ok := getPropertyKey(line); ok {
+			if _, found := getPropertyValue(base, key); found {
+				overrides[key] = line
+				continue
+			}
+		}
+		added = append(added, line)
+	}
+
+	lines := []string{}
+	for _, line := range strings.Split(strings.TrimRight(base, "\n"), "\n") {
+		if key, ok := getPropertyKey(line); ok {
+			if o, found := overrides[key]; found {
+				line = o
+			}
+		}
+		lines = append(lines, line)
+	}
+	if len(strings.TrimSpace(strings.Join(added, "\n"))) > 0 {
+		lines = append(lines, added...)
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// getPropertyKey returns the key of a properties line, false for comments and blank lines.
+func getPropertyKey(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+		return "", false
+	}
+	i := strings.IndexAny(line, "=:")
+	if i < 0 {
+		return "", false
+	}
+	return strings.TrimSpace(line[:i]), true
+}
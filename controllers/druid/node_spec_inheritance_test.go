@@ -0,0 +1,79 @@
+package druid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestResolveNodeSpecs(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	hot := clusterSpec.Spec.Nodes["historicals"]
+	hot.RestartedAt = "2023-01-01T00:00:00Z"
+	clusterSpec.Spec.Nodes["historicals"] = hot
+	clusterSpec.Spec.Nodes["historicals-cold"] = v1alpha1.DruidNodeSpec{
+		Extends:  "historicals",
+		Replicas: 4,
+		RuntimeProperties: `druid.processing.numThreads=2
+druid.server.tier=cold`,
+		Resources: v1.ResourceRequirements{
+			Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("8Gi")},
+		},
+	}
+
+	nodes, err := ResolveNodeSpecs(clusterSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cold := nodes["historicals-cold"]
+
+	if cold.NodeType != historical || cold.DruidPort != 8080 || cold.Replicas != 4 || cold.Extends != "" || cold.RestartedAt != "" {
+		t.Errorf("unexpected resolved fields %s %d %d [%s] [%s]", cold.NodeType, cold.DruidPort, cold.Replicas, cold.Extends, cold.RestartedAt)
+	}
+	if len(cold.VolumeClaimTemplates) != 1 || cold.ExtraJvmOptions != hot.ExtraJvmOptions {
+		t.Errorf("lists and options must be inherited")
+	}
+	memory, cpu := cold.Resources.Limits[v1.ResourceMemory], cold.Resources.Limits[v1.ResourceCPU]
+	if memory.String() != "8Gi" || cpu.String() != "4" {
+		t.Errorf("resources must be merged, got [%s] [%s]", memory.String(), cpu.String())
+	}
+
+	expected := `druid.service=druid/historical
+druid.server.http.numThreads=10
+druid.processing.buffer.sizeBytes=268435456
+druid.processing.numMergeBuffers=1
+druid.processing.numThreads=2
+# Segment storage
+druid.segmentCache.locations=[{\"path\":\"/druid/data/segments\",\"maxSize\":10737418240}]
+druid.server.maxSize=10737418240
+druid.server.tier=cold
+`
+	if cold.RuntimeProperties != expected {
+		t.Errorf("expected properties [%s], got [%s]", expected, cold.RuntimeProperties)
+	}
+
+	// inherited fields required by verification are not missing
+	if _, err := verifyDruidSpec(clusterSpec); err != nil && strings.Contains(err.Error(), "Node[historicals-cold] missing NodeType") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestResolveNodeSpecsErrors(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	clusterSpec.Spec.Nodes["a"] = v1alpha1.DruidNodeSpec{Extends: "b"}
+	clusterSpec.Spec.Nodes["b"] = v1alpha1.DruidNodeSpec{Extends: "a"}
+	if _, err := ResolveNodeSpecs(clusterSpec); err == nil || !strings.Contains(err.Error(), "inheritance cycle") {
+		t.Errorf("inheritance cycle must be rejected, got [%v]", err)
+	}
+	if _, err := verifyDruidSpec(clusterSpec); err == nil || !strings.Contains(err.Error(), "inheritance cycle") {
+		t.Errorf("inheritance cycle must be reported by verifyDruidSpec, got [%v]", err)
+	}
+
+	delete(clusterSpec.Spec.Nodes, "b")
+	if _, err := ResolveNodeSpecs(clusterSpec); err == nil || !strings.Contains(err.Error(), "Node[a] extends unknown node spec [b]") {
+		t.Errorf("unknown node spec must be rejected, got [%v]", err)
+	}
+}
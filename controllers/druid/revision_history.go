@@ -0,0 +1,211 @@
+package druid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	rollbackToAnnotation        = "druid.apache.org/rollback-to"
+	druidSpecHashLabel          = "druid_spec_hash"
+	defaultRevisionHistoryLimit = 10
+)
+
+// getDruidSpecHash returns a hash of the spec safe to be used in k8s resource names.
+func getDruidSpecHash(spec *v1alpha1.DruidSpec) (string, error) {
+	bytes, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	hasher := fnv.New32a()
+	hasher.Write(bytes)
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32())), nil
+}
+
+func makeControllerRevision(m *v1alpha1.Druid, hash string, revision int64) (*appsv1.ControllerRevision, error) {
+	bytes, err := json.Marshal(m.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := makeLabelsForDruid(m.Name)
+	labels[druidSpecHashLabel] = hash
+
+	cr := &appsv1.ControllerRevision{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "ControllerRevision",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", m.Name, hash),
+			Namespace: m.Namespace,
+			Labels:    labels,
+		},
+		Data:     runtime.RawExtension{Raw: bytes},
+		Revision: revision,
+	}
+	addOwnerRefToObject(cr, asOwner(m))
+	return cr, nil
+}
+
+func makeControllerRevisionListEmptyObj() *appsv1.ControllerRevisionList {
+	return &appsv1.ControllerRevisionList{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "ControllerRevision",
+		},
+	}
+}
+
+// listControllerRevisions returns the revisions of the druid CR sorted by revision number.
+func listControllerRevisions(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEmitter) ([]*appsv1.ControllerRevision, error) {
+	list, err := readers.List(context.TODO(), sdk, m, makeLabelsForDruid(m.Name), emitEvents, func() objectList { return makeControllerRevisionListEmptyObj() }, func(listObj runtime.Object) []object {
+		items := listObj.(*appsv1.ControllerRevisionList).Items
+		result := make([]object, len(items))
+		for i := 0; i < len(items); i++ {
+			result[i] = &items[i]
+		}
+		return result
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*appsv1.ControllerRevision, 0, len(list))
+	for _, obj := range list {
+		revisions = append(revisions, obj.(*appsv1.ControllerRevision))
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	return revisions, nil
+}
+
+// recordDruidSpecRevision stores the applied spec as a ControllerRevision, bumps the revision if the spec
+// matches an older one, prunes revisions above the history limit and patches current/previous revision in status.
+func recordDruidSpecRevision(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEmitter) error {
+	hash, err := getDruidSpecHash(&m.Spec)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := listControllerRevisions(sdk, m, emitEvents)
+	if err != nil {
+		return err
+	}
+
+	var maxRevision int64
+	var current *appsv1.ControllerRevision
+	for _, r := range revisions {
+		if r.Revision > maxRevision {
+			maxRevision = r.Revision
+		}
+		if r.Labels[druidSpecHashLabel] == hash {
+			current = r
+		}
+	}
+
+	if current == nil {
+		current, err = makeControllerRevision(m, hash, maxRevision+1)
+		if err != nil {
+			return err
+		}
+		if _, err := writers.Create(context.TODO(), sdk, m, current, emitEvents); err != nil {
+			return err
+		}
+		revisions = append(revisions, current)
+	} else if current.Revision != maxRevision {
+		// spec went back to an older revision, it becomes the latest one.
+		current.Revision = maxRevision + 1
+		if _, err := writers.Update(context.TODO(), sdk, m, current, emitEvents); err != nil {
+			return err
+		}
+		sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	}
+
+	limit := defaultRevisionHistoryLimit
+	if m.Spec.RevisionHistoryLimit != nil {
+		limit = int(*m.Spec.RevisionHistoryLimit)
+	}
+	for len(revisions) > limit && revisions[0] != current {
+		if err := writers.Delete(context.TODO(), sdk, m, revisions[0], emitEvents); err != nil {
+			return err
+		}
+		revisions = revisions[1:]
+	}
+
+	var previousRevision int64
+	for _, r := range revisions {
+		if r.Revision < current.Revision && r.Revision > previousRevision {
+			previousRevision = r.Revision
+		}
+	}
+
+	if m.Status.CurrentRevision != current.Revision || m.Status.PreviousRevision != previousRevision {
+		if err := druidStatusFieldsPatcher(sdk, map[string]interface{}{
+			"currentRevision":  current.Revision,
+			"previousRevision": previousRevision,
+		}, m, emitEvents); err != nil {
+			return err
+		}
+		m.Status.CurrentRevision = current.Revision
+		m.Status.PreviousRevision = previousRevision
+	}
+	return nil
+}
+
+// rollbackDruidSpec re-applies the spec stored in the revision requested by the rollback-to annotation.
+// Returns true if the CR has been updated, the rollback is then deployed on the next reconcile.
+func rollbackDruidSpec(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEmitter) (bool, error) {
+	value, ok := m.GetAnnotations()[rollbackToAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	// the annotation is removed in any case, a failed rollback must be requested again.
+	delete(m.Annotations, rollbackToAnnotation)
+
+	revision, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		e := fmt.Errorf("invalid revision [%s] in annotation [%s]", value, rollbackToAnnotation)
+		emitEvents.EmitEventGeneric(m, "DruidOperatorRollbackFail", "", e)
+		_, err := writers.Update(context.TODO(), sdk, m, m, emitEvents)
+		return true, err
+	}
+
+	revisions, err := listControllerRevisions(sdk, m, emitEvents)
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range revisions {
+		if r.Revision == revision {
+			spec := v1alpha1.DruidSpec{}
+			if err := json.Unmarshal(r.Data.Raw, &spec); err != nil {
+				return false, fmt.Errorf("failed to unmarshal revision [%s] due to [%s]", r.Name, err.Error())
+			}
+			m.Spec = spec
+			if _, err := writers.Update(context.TODO(), sdk, m, m, emitEvents); err != nil {
+				return false, err
+			}
+			msg := fmt.Sprintf("Rolled back spec of CR [%s] to revision [%d]", m.Name, revision)
+			logger.Info(msg, "name", m.Name, "namespace", m.Namespace)
+			emitEvents.EmitEventGeneric(m, "DruidOperatorRollbackSuccess", msg, nil)
+			return true, nil
+		}
+	}
+
+	e := fmt.Errorf("revision [%d] not found in the revision history of CR [%s]", revision, m.Name)
+	emitEvents.EmitEventGeneric(m, "DruidOperatorRollbackFail", "", e)
+	_, err = writers.Update(context.TODO(), sdk, m, m, emitEvents)
+	return true, err
+}
@@ -0,0 +1,41 @@
+package druid
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+func TestMakeControllerRevision(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+
+	hash, err := getDruidSpecHash(&clusterSpec.Spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := makeControllerRevision(clusterSpec, hash, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual.Name != clusterSpec.Name+"-"+hash || actual.Revision != 3 || actual.Labels[druidSpecHashLabel] != hash {
+		t.Errorf("unexpected revision [%s:%d]", actual.Name, actual.Revision)
+	}
+
+	spec := v1alpha1.DruidSpec{}
+	if err := json.Unmarshal(actual.Data.Raw, &spec); err != nil {
+		t.Fatal(err)
+	}
+	specHash, _ := getDruidSpecHash(&spec)
+	if specHash != hash {
+		t.Errorf("stored spec does not match the applied spec")
+	}
+
+	clusterSpec.Spec.Image = "changed"
+	changed, _ := getDruidSpecHash(&clusterSpec.Spec)
+	if changed == hash {
+		t.Errorf("spec hash must change with the spec")
+	}
+}
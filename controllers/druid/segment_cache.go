@@ -0,0 +1,125 @@
+package druid
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultSegmentCacheHeadroomPercent = 10
+	defaultSegmentCacheDirectory       = "segments"
+)
+
+// segmentCacheVolume is a volume claim template mounted in the druid container of a node.
+type segmentCacheVolume struct {
+	name      string
+	mountPath string
+	size      int64
+}
+
+// getSegmentCacheVolumes returns the volume claim templates of the node mounted in the druid container,
+// restricted to names if not empty.
+func getSegmentCacheVolumes(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, names []string) []segmentCacheVolume {
+	volumes := []segmentCacheVolume{}
+	for _, pvc := range getPersistentVolumeClaim(nodeSpec, m) {
+		if len(names) > 0 && !ContainsString(names, pvc.Name) {
+			continue
+		}
+		mountPath := getVolumeMountPath(nodeSpec, m, pvc.Name)
+		if mountPath == "" {
+			continue
+		}
+		size := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+		volumes = append(volumes, segmentCacheVolume{name: pvc.Name, mountPath: mountPath, size: size.Value()})
+	}
+	return volumes
+}
+
+// isPathUnder returns true if p is dir or a path in dir.
+func isPathUnder(p, dir string) bool {
+	dir = strings.TrimSuffix(dir, "/")
+	return p == dir || strings.HasPrefix(p, dir+"/")
+}
+
+// makeSegmentCacheRuntimeProperties returns the segment cache locations and server maxSize generated from the volumes
+// of the historical, each volume holding a location of its size minus the headroom.
+func makeSegmentCacheRuntimeProperties(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) (string, error) {
+	if nodeSpec.SegmentCache == nil || nodeSpec.NodeType != historical {
+		return "", nil
+	}
+
+	volumes := getSegmentCacheVolumes(nodeSpec, m, nodeSpec.SegmentCache.VolumeClaimTemplates)
+	if len(volumes) == 0 {
+		return "", fmt.Errorf("no volume claim template mounted for the segment cache")
+	}
+	headroom := int64(defaultSegmentCacheHeadroomPercent)
+	if nodeSpec.SegmentCache.HeadroomPercent != nil {
+		headroom = int64(*nodeSpec.SegmentCache.HeadroomPercent)
+	}
+
+	locations := []map[string]interface{}{}
+	var maxSize int64
+	for _, volume := range volumes {
+		size := volume.size / 100 * (100 - headroom)
+		locations = append(locations, map[string]interface{}{
+			"path":    path.Join(volume.mountPath, firstNonEmptyStr(nodeSpec.SegmentCache.Directory, defaultSegmentCacheDirectory)),
+			"maxSize": size,
+		})
+		maxSize += size
+	}
+	bytes, err := json.Marshal(locations)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s=%s\n%s=%d\n", segmentCacheLocationsProperty, string(bytes), serverMaxSizeProperty, maxSize), nil
+}
+
+// validateSegmentCacheProperties returns an error if the handwritten druid.server.maxSize or segment cache locations
+// of a historical exceed the size of its mounted volume claim templates. Historicals without volume claim templates
+// are not checked.
+func validateSegmentCacheProperties(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) error {
+	if nodeSpec.NodeType != historical || nodeSpec.SegmentCache != nil {
+		return nil
+	}
+	volumes := getSegmentCacheVolumes(nodeSpec, m, nil)
+	if len(volumes) == 0 {
+		return nil
+	}
+
+	var total int64
+	for _, volume := range volumes {
+		total += volume.size
+	}
+	if value, found := getPropertyValue(nodeSpec.RuntimeProperties, serverMaxSizeProperty); found {
+		if size, err := parseDruidBytes(value); err == nil && size > total {
+			return fmt.Errorf("%s [%d] exceeds the size [%d] of the mounted volumes", serverMaxSizeProperty, size, total)
+		}
+	}
+
+	value, found := getPropertyValue(nodeSpec.RuntimeProperties, segmentCacheLocationsProperty)
+	if !found {
+		return nil
+	}
+	locations := []map[string]interface{}{}
+	if err := json.Unmarshal([]byte(value), &locations); err != nil {
+		return nil
+	}
+	for _, volume := range volumes {
+		var used int64
+		for _, location := range locations {
+			p, _ := location["path"].(string)
+			if size, err := parseDruidBytes(location["maxSize"]); err == nil && isPathUnder(p, volume.mountPath) {
+				used += size
+			}
+		}
+		if used > volume.size {
+			return fmt.Errorf("%s on volume [%s] use [%d] which exceeds its size [%d]", segmentCacheLocationsProperty, volume.name, used, volume.size)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package druid
+
+import (
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+func TestMakeSegmentCacheRuntimeProperties(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	nodeSpec := clusterSpec.Spec.Nodes["historicals"]
+	nodeSpec.SegmentCache = &v1alpha1.SegmentCacheSpec{}
+
+	actual, err := makeSegmentCacheRuntimeProperties(&nodeSpec, clusterSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2Gi volume less 10% headroom
+	expected := `druid.segmentCache.locations=[{"maxSize":1932735240,"path":"/druid/data/segments"}]
+druid.server.maxSize=1932735240
+`
+	if actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	headroom := int32(50)
+	nodeSpec.SegmentCache = &v1alpha1.SegmentCacheSpec{HeadroomPercent: &headroom, Directory: "cache"}
+	actual, err = makeSegmentCacheRuntimeProperties(&nodeSpec, clusterSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `druid.segmentCache.locations=[{"maxSize":1073741800,"path":"/druid/data/cache"}]
+druid.server.maxSize=1073741800
+`
+	if actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	nodeSpec.SegmentCache = &v1alpha1.SegmentCacheSpec{VolumeClaimTemplates: []string{"unknown"}}
+	if _, err := makeSegmentCacheRuntimeProperties(&nodeSpec, clusterSpec); err == nil {
+		t.Errorf("segment cache without mounted volume must be rejected")
+	}
+}
+
+func TestValidateSegmentCacheProperties(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	nodeSpec := clusterSpec.Spec.Nodes["historicals"]
+
+	nodeSpec.RuntimeProperties = `druid.segmentCache.locations=[{"path":"/druid/data/segments","maxSize":"1g"}]
+druid.server.maxSize=1g`
+	if err := validateSegmentCacheProperties(&nodeSpec, clusterSpec); err != nil {
+		t.Errorf("expected valid properties, got [%v]", err)
+	}
+
+	nodeSpec.RuntimeProperties = `druid.server.maxSize=10737418240`
+	if err := validateSegmentCacheProperties(&nodeSpec, clusterSpec); err == nil {
+		t.Errorf("maxSize larger than the volume must be rejected")
+	}
+
+	nodeSpec.RuntimeProperties = `druid.segmentCache.locations=[{"path":"/druid/data/a","maxSize":"2g"},{"path":"/druid/data/b","maxSize":"2g"},{"path":"/tmp","maxSize":"10g"}]`
+	if err := validateSegmentCacheProperties(&nodeSpec, clusterSpec); err == nil {
+		t.Errorf("locations larger than the volume must be rejected")
+	}
+
+	nodeSpec.RuntimeProperties = `druid.segmentCache.locations=[{"path":"/tmp","maxSize":"10g"}]`
+	if err := validateSegmentCacheProperties(&nodeSpec, clusterSpec); err != nil {
+		t.Errorf("locations outside of the volumes must not be checked, got [%v]", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 
 	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	jsonpatch "github.com/evanphx/json-patch"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -54,6 +55,52 @@ func druidClusterStatusPatcher(sdk client.Client, updatedStatus v1alpha1.DruidCl
 	return nil
 }
 
+// druidStatusFieldsPatcher patches only the given fields of druid cluster status.
+// To be used for state which must be persisted before the end of the reconcile, the object is refreshed by the patch.
+func druidStatusFieldsPatcher(sdk client.Client, fields map[string]interface{}, m *v1alpha1.Druid, emitEvent EventEmitter) error {
+	patchBytes, err := json.Marshal(map[string]interface{}{"status": fields})
+	if err != nil {
+		return fmt.Errorf("failed to serialize status patch to bytes: %v", err)
+	}
+	return writers.Patch(context.TODO(), sdk, m, m, true, client.RawPatch(types.MergePatchType, patchBytes), emitEvent)
+}
+
+// druidObjectStatusPatcher patches the status of druid operator CRs other than Druid, the object is refreshed by the patch.
+func druidObjectStatusPatcher(sdk client.Client, obj client.Object, status interface{}) error {
+	patchBytes, err := makeObjectStatusPatch(obj, status)
+	if err != nil {
+		return err
+	}
+	return sdk.Status().Patch(context.TODO(), obj, client.RawPatch(types.MergePatchType, patchBytes))
+}
+
+// makeObjectStatusPatch returns the merge patch from the current status of the object to status. Fields dropped from
+// status as omitempty, such as synced=false, are removed by the patch rather than left unchanged.
+func makeObjectStatusPatch(obj client.Object, status interface{}) ([]byte, error) {
+	current, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	currentStatus := struct {
+		Status json.RawMessage `json:"status,omitempty"`
+	}{}
+	if err := json.Unmarshal(current, &currentStatus); err != nil {
+		return nil, err
+	}
+	original, err := json.Marshal(map[string]interface{}{"status": currentStatus.Status})
+	if err != nil {
+		return nil, err
+	}
+	if len(currentStatus.Status) == 0 {
+		original = []byte(`{"status":{}}`)
+	}
+	modified, err := json.Marshal(map[string]interface{}{"status": status})
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.CreateMergePatch(original, modified)
+}
+
 // In case of state change, patch the status and emit event.
 // emit events only on state change, to avoid event pollution.
 func druidNodeConditionStatusPatch(
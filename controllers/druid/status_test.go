@@ -0,0 +1,35 @@
+package druid
+
+import (
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+)
+
+func TestMakeObjectStatusPatch(t *testing.T) {
+	rule := &v1alpha1.DruidRule{Status: v1alpha1.DruidRuleStatus{Synced: true, Message: "Rules applied to the coordinator"}}
+	status := *rule.Status.DeepCopy()
+	status.Synced = false
+	status.Message = "coordinator unavailable"
+
+	patch, err := makeObjectStatusPatch(rule, status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"status":{"message":"coordinator unavailable","synced":null}}`; string(patch) != expected {
+		t.Errorf("expected patch [%s], got [%s]", expected, string(patch))
+	}
+
+	sup := &v1alpha1.DruidSupervisor{Status: v1alpha1.DruidSupervisorStatus{Healthy: true, State: "RUNNING"}}
+	supStatus := *sup.Status.DeepCopy()
+	supStatus.Healthy = false
+	supStatus.State = "UNHEALTHY_SUPERVISOR"
+	if patch, err := makeObjectStatusPatch(sup, supStatus); err != nil || string(patch) != `{"status":{"healthy":null,"state":"UNHEALTHY_SUPERVISOR"}}` {
+		t.Errorf("unexpected patch [%s] [%v]", string(patch), err)
+	}
+
+	// first status of an object
+	if patch, err := makeObjectStatusPatch(&v1alpha1.DruidRule{}, v1alpha1.DruidRuleStatus{Synced: true}); err != nil || string(patch) != `{"status":{"synced":true}}` {
+		t.Errorf("unexpected patch [%s] [%v]", string(patch), err)
+	}
+}
@@ -0,0 +1,302 @@
+package druid
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultTaskAutoscalerTargetUtilizationPercent = 100
+	defaultTaskAutoscalerScaleUpCooldownSeconds   = 60
+	defaultTaskAutoscalerScaleDownCooldownSeconds = 300
+)
+
+// druidWorker is a worker as listed by the overlord, disabled workers have an empty version.
+type druidWorker struct {
+	Worker struct {
+		Host     string `json:"host"`
+		Capacity int32  `json:"capacity"`
+		Version  string `json:"version"`
+	} `json:"worker"`
+	CurrCapacityUsed int32    `json:"currCapacityUsed"`
+	RunningTasks     []string `json:"runningTasks"`
+}
+
+func (w druidWorker) isIdle() bool {
+	return w.CurrCapacityUsed == 0 && len(w.RunningTasks) == 0
+}
+
+func isTaskAutoscaled(nodeSpec *v1alpha1.DruidNodeSpec) bool {
+	return nodeSpec.TaskAutoscaler != nil && nodeSpec.Kind != "Deployment" &&
+		(nodeSpec.NodeType == middleManager || nodeSpec.NodeType == indexer)
+}
+
+// reconcileTaskAutoscaler returns the replicas of the node spec, scaled on the pending and running tasks of the overlord.
+// Pods to be removed by a scale down are disabled first, the statefulset is scaled down once they are idle.
+// The overlord is polled once the cluster is ready, failures are reported as events and keep the current replicas.
+func reconcileTaskAutoscaler(sdk client.Client, nodeSpec *v1alpha1.DruidNodeSpec, key, nodeSpecUniqueStr string, ls map[string]string, m *v1alpha1.Druid, emitEvents EventEmitter) (int32, error) {
+	spec := nodeSpec.TaskAutoscaler
+	status, found := m.Status.TaskAutoscaler[key]
+	if !found {
+		status = v1alpha1.TaskAutoscalerStatus{Replicas: nodeSpec.Replicas}
+	}
+	current := clampTaskAutoscalerReplicas(spec, status.Replicas)
+
+	if m.Status.DruidNodeStatus.DruidNodeConditionType != v1alpha1.DruidClusterReady {
+		return current, nil
+	}
+
+	c, err := newDruidAPIClient(sdk, m, overlord)
+	if err != nil {
+		return current, err
+	}
+	var pending, running []interface{}
+	var workers []druidWorker
+	for path, out := range map[string]interface{}{
+		"/druid/indexer/v1/pendingTasks": &pending,
+		"/druid/indexer/v1/runningTasks": &running,
+		"/druid/indexer/v1/workers":      &workers,
+	} {
+		if _, err := c.do(http.MethodGet, path, nil, out); err != nil {
+			emitEvents.EmitEventGeneric(m, "DruidTaskAutoscalerPollFail", "", fmt.Errorf("failed to poll task queue for node [%s] due to [%s]", key, err.Error()))
+			return current, nil
+		}
+	}
+
+	podList, err := readers.List(context.TODO(), sdk, m, ls, emitEvents, func() objectList { return makePodList() }, func(listObj runtime.Object) []object {
+		items := listObj.(*v1.PodList).Items
+		result := make([]object, len(items))
+		for i := 0; i < len(items); i++ {
+			result[i] = &items[i]
+		}
+		return result
+	})
+	if err != nil {
+		return current, err
+	}
+	pods := map[string]*v1.Pod{}
+	for _, obj := range podList {
+		pods[obj.GetName()] = obj.(*v1.Pod)
+	}
+
+	// workers of the node spec by pod name, and task slots of the enabled workers
+	nodeWorkers := map[string]druidWorker{}
+	var totalCapacity, otherCapacity, workerCapacity int32
+	for _, w := range workers {
+		pod := findPodByHost(w.Worker.Host, pods)
+		if pod != nil {
+			nodeWorkers[pod.Name] = w
+			if w.Worker.Capacity > workerCapacity {
+				workerCapacity = w.Worker.Capacity
+			}
+		}
+		if w.Worker.Version == "" {
+			continue
+		}
+		totalCapacity += w.Worker.Capacity
+		if pod == nil {
+			otherCapacity += w.Worker.Capacity
+		}
+	}
+	if workerCapacity == 0 {
+		workerCapacity = getWorkerCapacityProperty(nodeSpec)
+	}
+
+	desired := computeTaskAutoscalerReplicas(spec, int32(len(pending)), int32(len(running)), otherCapacity, workerCapacity)
+
+	updated := status
+	updated.Replicas = current
+	updated.DesiredReplicas = desired
+	updated.PendingTasks = int32(len(pending))
+	updated.RunningTasks = int32(len(running))
+	updated.TotalCapacity = totalCapacity
+
+	scaleDownTo := current
+	if desired > current && isTaskAutoscalerCooldownElapsed(status.LastScaleTime, spec.ScaleUpCooldownSeconds, defaultTaskAutoscalerScaleUpCooldownSeconds) {
+		updated.Replicas = desired
+		updated.Message = fmt.Sprintf("Scaled up from %d to %d replicas for %d pending and %d running tasks", current, desired, updated.PendingTasks, updated.RunningTasks)
+	} else if desired < current && isTaskAutoscalerCooldownElapsed(status.LastScaleTime, spec.ScaleDownCooldownSeconds, defaultTaskAutoscalerScaleDownCooldownSeconds) {
+		scaleDownTo = desired
+	}
+
+	// statefulset removes the pods of highest ordinals, they are disabled and removed once seen idle and disabled
+	disabled := []string{}
+	candidates := map[string]bool{}
+	idle := true
+	for i := current - 1; i >= scaleDownTo; i-- {
+		name := fmt.Sprintf("%s-%d", nodeSpecUniqueStr, i)
+		candidates[name] = true
+		disabled = append(disabled, name)
+
+		w, registered := nodeWorkers[name]
+		if registered && w.Worker.Version != "" {
+			if err := setWorkerEnabled(sdk, m, nodeSpec, nodeSpecUniqueStr, pods[name], false); err != nil {
+				emitEvents.EmitEventGeneric(m, "DruidTaskAutoscalerDisableFail", "", fmt.Errorf("failed to disable worker [%s] due to [%s]", name, err.Error()))
+			}
+			idle = false
+		}
+		if registered && !w.isIdle() {
+			idle = false
+		}
+		if idle {
+			updated.Replicas = i
+		}
+	}
+	if scaleDownTo < current {
+		if updated.Replicas < current {
+			updated.Message = fmt.Sprintf("Scaled down from %d to %d replicas for %d pending and %d running tasks", current, updated.Replicas, updated.PendingTasks, updated.RunningTasks)
+		} else {
+			updated.Message = fmt.Sprintf("Waiting for disabled workers to complete their tasks to scale down to %d replicas", desired)
+		}
+	}
+
+	// workers disabled for a scale down which is no longer desired are enabled back
+	for _, name := range status.DisabledWorkers {
+		if candidates[name] {
+			continue
+		}
+		if getPodOrdinal(name, nodeSpecUniqueStr) >= desired {
+			// to be removed once the scale down cooldown is elapsed
+			disabled = append(disabled, name)
+			continue
+		}
+		if w, registered := nodeWorkers[name]; registered && w.Worker.Version == "" {
+			if err := setWorkerEnabled(sdk, m, nodeSpec, nodeSpecUniqueStr, pods[name], true); err != nil {
+				emitEvents.EmitEventGeneric(m, "DruidTaskAutoscalerEnableFail", "", fmt.Errorf("failed to enable worker [%s] due to [%s]", name, err.Error()))
+				disabled = append(disabled, name)
+			}
+		}
+	}
+	// removed pods are not disabled anymore
+	updated.DisabledWorkers = nil
+	for _, name := range disabled {
+		if getPodOrdinal(name, nodeSpecUniqueStr) < updated.Replicas {
+			updated.DisabledWorkers = append(updated.DisabledWorkers, name)
+		}
+	}
+
+	if updated.Replicas != current {
+		now := metav1.Now()
+		updated.LastScaleTime = &now
+		logger.Info(updated.Message, "name", m.Name, "namespace", m.Namespace, "node", key)
+		emitEvents.EmitEventGeneric(m, "DruidTaskAutoscalerScaled", fmt.Sprintf("Node [%s]: %s", key, updated.Message), nil)
+	}
+
+	if found && reflect.DeepEqual(updated, status) {
+		return updated.Replicas, nil
+	}
+	return updated.Replicas, patchTaskAutoscalerStatus(sdk, m, key, updated, emitEvents)
+}
+
+// computeTaskAutoscalerReplicas returns the replicas giving enough task slots for the pending and running tasks at
+// the target utilization, on top of the slots of the workers of other node specs.
+func computeTaskAutoscalerReplicas(spec *v1alpha1.TaskAutoscalerSpec, pending, running, otherCapacity, workerCapacity int32) int32 {
+	target := int32(defaultTaskAutoscalerTargetUtilizationPercent)
+	if spec.TargetUtilizationPercent != nil && *spec.TargetUtilizationPercent > 0 {
+		target = *spec.TargetUtilizationPercent
+	}
+	if workerCapacity < 1 {
+		workerCapacity = 1
+	}
+
+	needed := ((pending+running)*100+target-1)/target - otherCapacity
+	replicas := int32(0)
+	if needed > 0 {
+		replicas = (needed + workerCapacity - 1) / workerCapacity
+	}
+	return clampTaskAutoscalerReplicas(spec, replicas)
+}
+
+func clampTaskAutoscalerReplicas(spec *v1alpha1.TaskAutoscalerSpec, replicas int32) int32 {
+	if replicas < spec.MinReplicas {
+		return spec.MinReplicas
+	}
+	if replicas > spec.MaxReplicas {
+		return spec.MaxReplicas
+	}
+	return replicas
+}
+
+func isTaskAutoscalerCooldownElapsed(lastScaleTime *metav1.Time, cooldownSeconds *int32, defaultCooldownSeconds int32) bool {
+	if lastScaleTime == nil {
+		return true
+	}
+	cooldown := defaultCooldownSeconds
+	if cooldownSeconds != nil {
+		cooldown = *cooldownSeconds
+	}
+	return time.Now().After(lastScaleTime.Add(time.Duration(cooldown) * time.Second))
+}
+
+// getWorkerCapacityProperty returns druid.worker.capacity of the node properties, used until a worker registers.
+func getWorkerCapacityProperty(nodeSpec *v1alpha1.DruidNodeSpec) int32 {
+	if value, found := getPropertyValue(nodeSpec.RuntimeProperties, "druid.worker.capacity"); found {
+		if capacity, err := strconv.Atoi(value); err == nil && capacity > 0 {
+			return int32(capacity)
+		}
+	}
+	return 1
+}
+
+// getPodOrdinal returns the ordinal of the statefulset pod, -1 if the name is not the one of a pod of the statefulset.
+func getPodOrdinal(podName, stsName string) int32 {
+	if !strings.HasPrefix(podName, stsName+"-") {
+		return -1
+	}
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(podName, stsName+"-"))
+	if err != nil {
+		return -1
+	}
+	return int32(ordinal)
+}
+
+// setWorkerEnabled enables or disables the worker running in the pod, disabled workers are not assigned new tasks.
+func setWorkerEnabled(sdk client.Client, m *v1alpha1.Druid, nodeSpec *v1alpha1.DruidNodeSpec, nodeSpecUniqueStr string, pod *v1.Pod, enabled bool) error {
+	if pod == nil || pod.Status.PodIP == "" {
+		return fmt.Errorf("pod has no IP")
+	}
+	c, err := newDruidHostAPIClient(sdk, m, nodeSpecUniqueStr, pod.Status.PodIP, nodeSpec.DruidPort,
+		fmt.Sprintf("%s.%s.%s.svc", pod.Name, pod.Spec.Subdomain, pod.Namespace))
+	if err != nil {
+		return err
+	}
+	path := "/druid/worker/v1/disable"
+	if enabled {
+		path = "/druid/worker/v1/enable"
+	}
+	_, err = c.do(http.MethodPost, path, nil, nil)
+	return err
+}
+
+// patchTaskAutoscalerStatus patches the status of the node spec, and removes the ones of node specs not autoscaled anymore.
+func patchTaskAutoscalerStatus(sdk client.Client, m *v1alpha1.Druid, key string, status v1alpha1.TaskAutoscalerStatus, emitEvents EventEmitter) error {
+	fields := map[string]interface{}{key: status}
+	taskAutoscaler := map[string]v1alpha1.TaskAutoscalerStatus{key: status}
+	for k, v := range m.Status.TaskAutoscaler {
+		if k == key {
+			continue
+		}
+		if nodeSpec, ok := getNodeSpecs(m)[k]; ok && isTaskAutoscaled(&nodeSpec) {
+			taskAutoscaler[k] = v
+		} else {
+			fields[k] = nil
+		}
+	}
+
+	if err := druidStatusFieldsPatcher(sdk, map[string]interface{}{"taskAutoscaler": fields}, m, emitEvents); err != nil {
+		return err
+	}
+	m.Status.TaskAutoscaler = taskAutoscaler
+	return nil
+}
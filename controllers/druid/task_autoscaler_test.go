@@ -0,0 +1,57 @@
+package druid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestComputeTaskAutoscalerReplicas(t *testing.T) {
+	fifty := int32(50)
+	tests := []struct {
+		name          string
+		spec          v1alpha1.TaskAutoscalerSpec
+		pending       int32
+		running       int32
+		otherCapacity int32
+		capacity      int32
+		expected      int32
+	}{
+		{"idle cluster scales to min", v1alpha1.TaskAutoscalerSpec{MinReplicas: 1, MaxReplicas: 10}, 0, 0, 0, 2, 1},
+		{"slots for pending and running tasks", v1alpha1.TaskAutoscalerSpec{MinReplicas: 0, MaxReplicas: 10}, 3, 2, 0, 2, 3},
+		{"slots of other workers are used first", v1alpha1.TaskAutoscalerSpec{MinReplicas: 0, MaxReplicas: 10}, 3, 2, 4, 2, 1},
+		{"target utilization", v1alpha1.TaskAutoscalerSpec{MinReplicas: 0, MaxReplicas: 10, TargetUtilizationPercent: &fifty}, 2, 2, 0, 2, 4},
+		{"bounded by max", v1alpha1.TaskAutoscalerSpec{MinReplicas: 0, MaxReplicas: 3}, 20, 0, 0, 2, 3},
+	}
+	for _, tt := range tests {
+		if replicas := computeTaskAutoscalerReplicas(&tt.spec, tt.pending, tt.running, tt.otherCapacity, tt.capacity); replicas != tt.expected {
+			t.Errorf("%s: expected [%d] replicas, got [%d]", tt.name, tt.expected, replicas)
+		}
+	}
+}
+
+func TestIsTaskAutoscalerCooldownElapsed(t *testing.T) {
+	if !isTaskAutoscalerCooldownElapsed(nil, nil, defaultTaskAutoscalerScaleDownCooldownSeconds) {
+		t.Errorf("cooldown must be elapsed if never scaled")
+	}
+	now := metav1.Now()
+	if isTaskAutoscalerCooldownElapsed(&now, nil, defaultTaskAutoscalerScaleDownCooldownSeconds) {
+		t.Errorf("cooldown must not be elapsed right after scaling")
+	}
+	zero := int32(0)
+	past := metav1.NewTime(now.Add(-time.Second))
+	if !isTaskAutoscalerCooldownElapsed(&past, &zero, defaultTaskAutoscalerScaleDownCooldownSeconds) {
+		t.Errorf("zero cooldown must be elapsed")
+	}
+}
+
+func TestGetPodOrdinal(t *testing.T) {
+	if ordinal := getPodOrdinal("druid-mm-12", "druid-mm"); ordinal != 12 {
+		t.Errorf("expected ordinal 12, got [%d]", ordinal)
+	}
+	if ordinal := getPodOrdinal("druid-broker-0", "druid-mm"); ordinal != -1 {
+		t.Errorf("expected no ordinal, got [%d]", ordinal)
+	}
+}
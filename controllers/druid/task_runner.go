@@ -0,0 +1,293 @@
+package druid
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	"github.com/ghodss/yaml"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	taskRunnerKubernetes        = "kubernetes"
+	overlordKubernetesExtension = "druid-kubernetes-overlord-extensions"
+	peonPodTemplateKey          = "peonPodTemplate.yaml"
+	peonNodeType                = "peon"
+	defaultPeonPort             = 8100
+	// label set by the overlord on the task Jobs, used to garbage collect them on deletion of the CR
+	taskRunnerLabel = "druid_task_runner"
+)
+
+var defaultPeonCommand = []string{"sh", "-c", "/peon.sh /druid/data 1"}
+
+func isKubernetesTaskRunner(nodeSpec *v1alpha1.DruidNodeSpec) bool {
+	return nodeSpec.NodeType == overlord && nodeSpec.TaskRunner == taskRunnerKubernetes
+}
+
+func hasKubernetesTaskRunner(m *v1alpha1.Druid) bool {
+	for _, nodeSpec := range getNodeSpecs(m) {
+		if isKubernetesTaskRunner(&nodeSpec) {
+			return true
+		}
+	}
+	return false
+}
+
+func makeTaskRunnerName(nodeSpecUniqueStr string) string {
+	return fmt.Sprintf("%s-task-runner", nodeSpecUniqueStr)
+}
+
+// getTaskRunnerServiceAccountName returns the ServiceAccount of the cluster if set, else the dedicated one of the
+// overlord.
+func getTaskRunnerServiceAccountName(m *v1alpha1.Druid, nodeSpecUniqueStr string) string {
+	return firstNonEmptyStr(m.Spec.ServiceAccount, makeTaskRunnerName(nodeSpecUniqueStr))
+}
+
+func makePeonNodeSpecUniqueStr(nodeSpecUniqueStr string) string {
+	return fmt.Sprintf("%s-peon", nodeSpecUniqueStr)
+}
+
+// makePeonNodeSpec returns the node spec the peon pods and config are built from, as for druid nodes.
+func makePeonNodeSpec(nodeSpec *v1alpha1.DruidNodeSpec) *v1alpha1.DruidNodeSpec {
+	t := nodeSpec.PeonTemplate
+	if t == nil {
+		t = &v1alpha1.PeonTemplateSpec{}
+	}
+	return &v1alpha1.DruidNodeSpec{
+		NodeType:          peonNodeType,
+		DruidPort:         defaultPeonPort,
+		RuntimeProperties: t.RuntimeProperties,
+		JvmOptions:        t.JvmOptions,
+		Image:             firstNonEmptyStr(t.Image, nodeSpec.Image),
+		ImagePullSecrets:  nodeSpec.ImagePullSecrets,
+		ImagePullPolicy:   nodeSpec.ImagePullPolicy,
+		Resources:         t.Resources,
+		Env:               t.Env,
+		NodeSelector:      t.NodeSelector,
+		Tolerations:       t.Tolerations,
+		Affinity:          t.Affinity,
+		PodAnnotations:    t.PodAnnotations,
+		PodLabels:         t.PodLabels,
+		VolumeMounts:      t.VolumeMounts,
+		Volumes:           t.Volumes,
+		Extensions:        nodeSpec.Extensions,
+	}
+}
+
+// makeTaskRunnerRuntimeProperties returns the overlord properties of the kubernetes task runner, using the peon pod
+// template mounted with the overlord config. Extension is added to the load list of the common properties.
+func makeTaskRunnerRuntimeProperties(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) (string, error) {
+	discoveryProp, err := makeDiscoveryRuntimeProperties(m)
+	if err != nil {
+		return "", err
+	}
+	extensionsProp, err := makeExtensionsRuntimeProperties(nodeSpec, m)
+	if err != nil {
+		return "", err
+	}
+	loadList, err := makeExtensionsLoadListProperty(
+		fmt.Sprintf("%s\n%s\n%s\n%s", m.Spec.CommonRuntimeProperties, discoveryProp, nodeSpec.RuntimeProperties, extensionsProp), overlordKubernetesExtension)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(`%sdruid.indexer.runner.type=k8s
+druid.indexer.runner.namespace=%s
+druid.indexer.task.encapsulatedTask=true
+druid.indexer.runner.k8s.adapter.type=customTemplateAdapter
+druid.indexer.runner.k8s.podTemplate.base=%s/%s
+druid.indexer.runner.labels={"%s":"%s"}
+`, loadList, m.Namespace, firstNonEmptyStr(nodeSpec.NodeConfigMountPath, getNodeConfigMountPath(nodeSpec)), peonPodTemplateKey,
+		taskRunnerLabel, m.Name), nil
+}
+
+// makePeonPodTemplate returns the PodTemplate yaml of the peons, built with the pod spec of druid nodes.
+// Peons mount the certificate of the overlord if TLS is enabled.
+func makePeonPodTemplate(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, nodeSpecUniqueStr string) (string, error) {
+	peon := makePeonNodeSpec(nodeSpec)
+
+	withoutTLS := *m
+	withoutTLS.Spec.TLS = nil
+	spec := makePodSpec(peon, &withoutTLS, makePeonNodeSpecUniqueStr(nodeSpecUniqueStr), "")
+
+	// sidecars would keep the Jobs running
+	spec.Containers = spec.Containers[:1]
+	main := &spec.Containers[0]
+	main.Name = "main"
+	main.Command = defaultPeonCommand
+	if nodeSpec.PeonTemplate != nil && nodeSpec.PeonTemplate.Command != nil {
+		main.Command = nodeSpec.PeonTemplate.Command
+	}
+	main.LivenessProbe = nil
+	main.ReadinessProbe = nil
+	main.StartupProbe = nil
+	main.Lifecycle = nil
+	spec.RestartPolicy = v1.RestartPolicyNever
+
+	if m.Spec.TLS != nil {
+		addTLSToPodSpec(&spec, m, nodeSpecUniqueStr)
+	}
+
+	template := &v1.PodTemplate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "PodTemplate",
+		},
+		Template: v1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      firstNonNilValue(peon.PodLabels, m.Spec.PodLabels).(map[string]string),
+				Annotations: makePodAnnotations(peon, m),
+			},
+			Spec: spec,
+		},
+	}
+	bytes, err := yaml.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// deployTaskRunner creates the config of the peons, and the Role allowing the overlord to manage the task Jobs and
+// their pods. The Role is bound to the ServiceAccount of the cluster, a dedicated one being created if none is set.
+func deployTaskRunner(sdk client.Client, nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, ls map[string]string, nodeSpecUniqueStr string,
+	configMapNames, serviceAccountNames, roleNames, roleBindingNames map[string]bool, emitEvents EventEmitter) error {
+	if !isKubernetesTaskRunner(nodeSpec) {
+		return nil
+	}
+
+	peonUniqueStr := makePeonNodeSpecUniqueStr(nodeSpecUniqueStr)
+	if _, err := sdkCreateOrUpdateAsNeeded(sdk,
+		func() (object, error) {
+			return makeConfigMapForNodeSpec(makePeonNodeSpec(nodeSpec), m, ls, peonUniqueStr)
+		},
+		func() object { return makeConfigMapEmptyObj() },
+		alwaysTrueIsEqualsFn, noopUpdaterFn, m, configMapNames, emitEvents); err != nil {
+		return err
+	}
+
+	name := makeTaskRunnerName(nodeSpecUniqueStr)
+	if m.Spec.ServiceAccount == "" {
+		// the ServiceAccount has no spec to reconcile, its secrets being managed by kubernetes
+		if _, err := sdkCreateOrUpdateAsNeeded(sdk,
+			func() (object, error) { return makeTaskRunnerServiceAccount(m, name, ls), nil },
+			func() object { return &v1.ServiceAccount{} },
+			alwaysTrueIsEqualsFn, noopUpdaterFn, m, serviceAccountNames, emitEvents); err != nil {
+			return err
+		}
+	}
+	if _, err := sdkCreateOrUpdateAsNeeded(sdk,
+		func() (object, error) { return makeTaskRunnerRole(m, name, ls), nil },
+		func() object { return &rbacv1.Role{} },
+		roleIsEquals, noopUpdaterFn, m, roleNames, emitEvents); err != nil {
+		return err
+	}
+	_, err := sdkCreateOrUpdateAsNeeded(sdk,
+		func() (object, error) {
+			return makeTaskRunnerRoleBinding(m, name, getTaskRunnerServiceAccountName(m, nodeSpecUniqueStr), ls), nil
+		},
+		func() object { return &rbacv1.RoleBinding{} },
+		roleBindingIsEquals, noopUpdaterFn, m, roleBindingNames, emitEvents)
+	return err
+}
+
+func makeTaskRunnerServiceAccount(m *v1alpha1.Druid, name string, ls map[string]string) *v1.ServiceAccount {
+	return &v1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: m.Namespace,
+			Labels:    ls,
+		},
+	}
+}
+
+func makeTaskRunnerRole(m *v1alpha1.Druid, name string, ls map[string]string) *rbacv1.Role {
+	return &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "Role",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: m.Namespace,
+			Labels:    ls,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"batch"},
+				Resources: []string{"jobs"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "list", "watch", "delete"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods/log"},
+				Verbs:     []string{"get"},
+			},
+		},
+	}
+}
+
+func makeTaskRunnerRoleBinding(m *v1alpha1.Druid, name, serviceAccountName string, ls map[string]string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "RoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: m.Namespace,
+			Labels:    ls,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      serviceAccountName,
+				Namespace: m.Namespace,
+			},
+		},
+	}
+}
+
+// deleteTaskRunnerJobs deletes the task Jobs left by the overlords of the CR, with their pods.
+func deleteTaskRunnerJobs(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEmitter) error {
+	jobs, err := readers.List(context.TODO(), sdk, m, map[string]string{taskRunnerLabel: m.Name}, emitEvents,
+		func() objectList { return &batchv1.JobList{} },
+		func(listObj runtime.Object) []object {
+			items := listObj.(*batchv1.JobList).Items
+			result := make([]object, len(items))
+			for i := 0; i < len(items); i++ {
+				result[i] = &items[i]
+			}
+			return result
+		})
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if err := writers.Delete(context.TODO(), sdk, m, job, emitEvents, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
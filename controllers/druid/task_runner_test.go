@@ -0,0 +1,71 @@
+package druid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestMakeConfigMapForKubernetesTaskRunner(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	nodeSpec := clusterSpec.Spec.Nodes["overlords"]
+	nodeSpec.TaskRunner = taskRunnerKubernetes
+	nodeSpecUniqueStr := makeNodeSpecificUniqueString(clusterSpec, "overlords")
+
+	cm, err := makeConfigMapForNodeSpec(&nodeSpec, clusterSpec, map[string]string{}, nodeSpecUniqueStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(cm.Data["runtime.properties"]), `{"druid_task_runner":"`+clusterSpec.Name+`"}`) {
+		t.Errorf("task runner properties must override the node properties")
+	}
+	for _, expected := range []string{
+		"druid.indexer.runner.type=k8s",
+		"druid.indexer.runner.namespace=" + clusterSpec.Namespace,
+		"druid.indexer.runner.k8s.podTemplate.base=" + firstNonEmptyStr(nodeSpec.NodeConfigMountPath, getNodeConfigMountPath(&nodeSpec)) + "/" + peonPodTemplateKey,
+		overlordKubernetesExtension,
+	} {
+		if !strings.Contains(cm.Data["runtime.properties"], expected) {
+			t.Errorf("expected [%s] in overlord properties [%s]", expected, cm.Data["runtime.properties"])
+		}
+	}
+
+	template := &v1.PodTemplate{}
+	if err := yaml.Unmarshal([]byte(cm.Data[peonPodTemplateKey]), template); err != nil {
+		t.Fatal(err)
+	}
+	spec := template.Template.Spec
+	if spec.RestartPolicy != v1.RestartPolicyNever || len(spec.Containers) != 1 || spec.Containers[0].Name != "main" {
+		t.Errorf("unexpected peon pod spec [%+v]", spec)
+	}
+	if spec.Containers[0].ReadinessProbe != nil || spec.Containers[0].LivenessProbe != nil {
+		t.Errorf("peon must not have probes")
+	}
+
+	if podSpec := makePodSpec(&nodeSpec, clusterSpec, nodeSpecUniqueStr, ""); podSpec.ServiceAccountName != makeTaskRunnerName(nodeSpecUniqueStr) {
+		t.Errorf("unexpected overlord ServiceAccount [%s]", podSpec.ServiceAccountName)
+	}
+	if binding := makeTaskRunnerRoleBinding(clusterSpec, makeTaskRunnerName(nodeSpecUniqueStr), getTaskRunnerServiceAccountName(clusterSpec, nodeSpecUniqueStr), nil); binding.Subjects[0].Name != makeTaskRunnerName(nodeSpecUniqueStr) {
+		t.Errorf("unexpected task runner RoleBinding subjects [%+v]", binding.Subjects)
+	}
+
+	// the ServiceAccount of the cluster is kept, the Role being bound to it
+	clusterSpec.Spec.ServiceAccount = "druid"
+	if podSpec := makePodSpec(&nodeSpec, clusterSpec, nodeSpecUniqueStr, ""); podSpec.ServiceAccountName != "druid" {
+		t.Errorf("overlord must keep the ServiceAccount of the cluster, got [%s]", podSpec.ServiceAccountName)
+	}
+	if binding := makeTaskRunnerRoleBinding(clusterSpec, makeTaskRunnerName(nodeSpecUniqueStr), getTaskRunnerServiceAccountName(clusterSpec, nodeSpecUniqueStr), nil); binding.Subjects[0].Name != "druid" {
+		t.Errorf("task runner Role must be bound to the ServiceAccount of the cluster, got [%+v]", binding.Subjects)
+	}
+	if binding := makeDiscoveryRoleBinding(clusterSpec, nil); len(binding.Subjects) != 1 || binding.Subjects[0].Name != "druid" {
+		t.Errorf("unexpected discovery RoleBinding subjects [%+v]", binding.Subjects)
+	}
+	clusterSpec.Spec.ServiceAccount = ""
+
+	other := clusterSpec.Spec.Nodes["brokers"]
+	if cm, _ := makeConfigMapForNodeSpec(&other, clusterSpec, map[string]string{}, "broker"); cm.Data[peonPodTemplateKey] != "" {
+		t.Errorf("peon pod template must only be added for overlords with the kubernetes task runner")
+	}
+}
@@ -0,0 +1,115 @@
+package druid
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const zookeeperHostProperty = "druid.zk.service.host"
+
+// makeTemplateData returns the variables available to the templates of the cluster config, and to the ones of the
+// node config if nodeSpec is not nil. Variables not known, such as ZookeeperHost without spec.zookeeper, are not set
+// so that templates using them fail.
+func makeTemplateData(m *v1alpha1.Druid, nodeSpec *v1alpha1.DruidNodeSpec, nodeSpecUniqueStr string) (map[string]interface{}, error) {
+	services := map[string]string{}
+	ports := map[string]int32{}
+	for key, n := range getNodeSpecs(m) {
+		if name := getFirstServiceName(&n, m, makeNodeSpecificUniqueString(m, key)); name != "" {
+			services[key] = name
+		}
+		ports[key] = n.DruidPort
+	}
+
+	data := map[string]interface{}{
+		"ClusterName": m.Name,
+		"Namespace":   m.Namespace,
+		"Services":    services,
+		"Ports":       ports,
+	}
+	if m.Spec.Zookeeper != nil {
+		zm, err := createZookeeperManager(m.Spec.Zookeeper)
+		if err != nil {
+			return nil, err
+		}
+		if host, found := getPropertyValue(zm.Configuration(), zookeeperHostProperty); found {
+			data["ZookeeperHost"] = host
+		}
+	}
+
+	if nodeSpec != nil {
+		data["NodeType"] = nodeSpec.NodeType
+		data["DruidPort"] = nodeSpec.DruidPort
+		data["NodeSpecName"] = nodeSpecUniqueStr
+		if name := getFirstServiceName(nodeSpec, m, nodeSpecUniqueStr); name != "" {
+			data["ServiceName"] = name
+		}
+	}
+	return data, nil
+}
+
+// getFirstServiceName returns the name of the first service of the node spec, empty if it has none.
+func getFirstServiceName(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, nodeSpecUniqueStr string) string {
+	services := firstNonNilValue(nodeSpec.Services, m.Spec.Services).([]v1.Service)
+	if len(services) == 0 {
+		return ""
+	}
+	return getServiceName(services[0].ObjectMeta.Name, nodeSpecUniqueStr)
+}
+
+// renderTemplate renders text as a go template, undefined variables being errors. Text without template action
+// is returned as is.
+func renderTemplate(name, text string, data map[string]interface{}) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template in %s due to [%s]", name, err.Error())
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render template in %s due to [%s]", name, err.Error())
+	}
+	return buf.String(), nil
+}
+
+// renderCommonRuntimeProperties returns the common runtime properties of the cluster with templates rendered.
+func renderCommonRuntimeProperties(m *v1alpha1.Druid) (string, error) {
+	data, err := makeTemplateData(m, nil, "")
+	if err != nil {
+		return "", err
+	}
+	return renderTemplate("common.runtime.properties", m.Spec.CommonRuntimeProperties, data)
+}
+
+// renderNodeSpecTemplates returns a copy of the node spec with the templates of its runtime properties, jvm options
+// and log4j config rendered, jvm options and log4j config of the cluster being copied to the node spec if not set,
+// unless the node spec overrides the logging of the cluster.
+func renderNodeSpecTemplates(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, nodeSpecUniqueStr string) (*v1alpha1.DruidNodeSpec, error) {
+	data, err := makeTemplateData(m, nodeSpec, nodeSpecUniqueStr)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := *nodeSpec
+	rendered.JvmOptions = firstNonEmptyStr(nodeSpec.JvmOptions, m.Spec.JvmOptions)
+	if nodeSpec.Logging == nil {
+		rendered.Log4jConfig = firstNonEmptyStr(nodeSpec.Log4jConfig, m.Spec.Log4jConfig)
+	}
+	for name, field := range map[string]*string{
+		"runtime.properties": &rendered.RuntimeProperties,
+		"jvm.options":        &rendered.JvmOptions,
+		"extra.jvm.options":  &rendered.ExtraJvmOptions,
+		"log4j.config":       &rendered.Log4jConfig,
+	} {
+		if *field, err = renderTemplate(name, *field, data); err != nil {
+			return nil, err
+		}
+	}
+	return &rendered, nil
+}
@@ -0,0 +1,46 @@
+package druid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderNodeSpecTemplates(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	nodeSpec := clusterSpec.Spec.Nodes["brokers"]
+	nodeSpec.RuntimeProperties = `druid.host={{ .ServiceName }}.{{ .Namespace }}.svc
+druid.plaintextPort={{ .DruidPort }}
+druid.zk.service.host={{ .ZookeeperHost }}
+druid.router.brokers={{ .Services.brokers }}:{{ index .Ports "coordinators" }}`
+	nodeSpec.ExtraJvmOptions = "-Ddruid.cluster={{ .ClusterName }}"
+
+	rendered, err := renderNodeSpecTemplates(&nodeSpec, clusterSpec, makeNodeSpecificUniqueString(clusterSpec, "brokers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `druid.host=druid-druid-test-brokers.test-namespace.svc
+druid.plaintextPort=8080
+druid.zk.service.host=zookeeper-0.zookeeper,zookeeper-1.zookeeper,zookeeper-2.zookeeper
+druid.router.brokers=druid-druid-test-brokers:8080`
+	if rendered.RuntimeProperties != expected {
+		t.Errorf("expected [%s], got [%s]", expected, rendered.RuntimeProperties)
+	}
+	if rendered.ExtraJvmOptions != "-Ddruid.cluster=druid-test" {
+		t.Errorf("unexpected jvm options [%s]", rendered.ExtraJvmOptions)
+	}
+	if rendered.JvmOptions != clusterSpec.Spec.JvmOptions || nodeSpec.ExtraJvmOptions != "-Ddruid.cluster={{ .ClusterName }}" {
+		t.Errorf("templates must be rendered in a copy with the cluster jvm options")
+	}
+
+	for _, properties := range []string{"druid.host={{ .Unknown }}", "druid.host={{ .Services.coordinators }}", "druid.host={{ .Ports"} {
+		nodeSpec.RuntimeProperties = properties
+		if _, err := renderNodeSpecTemplates(&nodeSpec, clusterSpec, "druid-druid-test-brokers"); err == nil {
+			t.Errorf("template [%s] must be rejected", properties)
+		}
+	}
+
+	clusterSpec.Spec.CommonRuntimeProperties = "druid.port={{ .DruidPort }}"
+	if _, err := verifyDruidSpec(clusterSpec); err == nil || !strings.Contains(err.Error(), "failed to render template in common.runtime.properties") {
+		t.Errorf("node variables must be undefined in common properties, got [%v]", err)
+	}
+}
@@ -0,0 +1,452 @@
+package druid
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	tlsProviderBuiltIn     = "BuiltIn"
+	tlsProviderCertManager = "CertManager"
+	defaultTLSDuration     = 90 * 24 * time.Hour
+	tlsCADuration          = 10 * 365 * 24 * time.Hour
+	// -jdktrust of the truststore requires openssl 3.2 or later
+	defaultTLSInitImage    = "alpine/openssl:3.3.2"
+	tlsCertsMountPath      = "/druid/tls/certs"
+	tlsKeystoresMountPath  = "/druid/tls/keystores"
+	tlsKeystorePasswordEnv = "DRUID_TLS_KEYSTORE_PASSWORD"
+	tlsKeystorePasswordKey = "password"
+	tlsCertAlias           = "druid"
+	tlsCAKey               = "ca.crt"
+	tlsCAPrivateKey        = "ca.key"
+)
+
+func makeTLSSecretName(nodeSpecUniqueStr string) string {
+	return fmt.Sprintf("%s-tls", nodeSpecUniqueStr)
+}
+
+func makeTLSCASecretName(m *v1alpha1.Druid) string {
+	return fmt.Sprintf("%s-druid-tls-ca", m.Name)
+}
+
+func makeTLSKeystorePasswordSecretName(m *v1alpha1.Druid) string {
+	return fmt.Sprintf("%s-druid-tls-keystore", m.Name)
+}
+
+func getTLSDuration(m *v1alpha1.Druid) time.Duration {
+	if m.Spec.TLS.Duration != nil {
+		return m.Spec.TLS.Duration.Duration
+	}
+	return defaultTLSDuration
+}
+
+// getTLSDNSNames returns the names of the services of the node spec, and the names of the pods behind them.
+func getTLSDNSNames(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, nodeSpecUniqueStr string) []string {
+	dnsNames := []string{}
+	for _, svc := range firstNonNilValue(nodeSpec.Services, m.Spec.Services).([]v1.Service) {
+		name := getServiceName(svc.ObjectMeta.Name, nodeSpecUniqueStr)
+		dnsNames = append(dnsNames,
+			name,
+			fmt.Sprintf("%s.%s.svc", name, m.Namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", name, m.Namespace),
+			fmt.Sprintf("*.%s.%s.svc", name, m.Namespace),
+			fmt.Sprintf("*.%s.%s.svc.cluster.local", name, m.Namespace))
+	}
+	sort.Strings(dnsNames)
+	return dnsNames
+}
+
+// makeTLSRuntimeProperties returns the properties enabling the TLS port on the druid port and disabling the plaintext one.
+// Hostnames are not validated unless enabled, the pods of Deployments having no name covered by the certificates.
+func makeTLSRuntimeProperties(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid) string {
+	if m.Spec.TLS == nil {
+		return ""
+	}
+	passwordProvider := fmt.Sprintf(`{"type":"environment","variable":"%s"}`, tlsKeystorePasswordEnv)
+	return fmt.Sprintf(`druid.enablePlaintextPort=false
+druid.enableTlsPort=true
+druid.tlsPort=%d
+druid.server.https.keyStoreType=PKCS12
+druid.server.https.keyStorePath=%s/keystore.p12
+druid.server.https.certAlias=%s
+druid.server.https.keyStorePassword=%s
+druid.client.https.protocol=TLSv1.2
+druid.client.https.trustStoreType=PKCS12
+druid.client.https.trustStorePath=%s/truststore.p12
+druid.client.https.trustStorePassword=%s
+druid.client.https.validateHostnames=%t
+`, nodeSpec.DruidPort, tlsKeystoresMountPath, tlsCertAlias, passwordProvider, tlsKeystoresMountPath, passwordProvider, m.Spec.TLS.ValidateHostnames)
+}
+
+// addTLSToPodSpec mounts the certificates of the node spec, builds the PKCS12 keystores from them in an init container
+// and switches the http probes to HTTPS.
+func addTLSToPodSpec(spec *v1.PodSpec, m *v1alpha1.Druid, nodeSpecUniqueStr string) {
+	passwordEnv := v1.EnvVar{
+		Name: tlsKeystorePasswordEnv,
+		ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: makeTLSKeystorePasswordSecretName(m)},
+				Key:                  tlsKeystorePasswordKey,
+			},
+		},
+	}
+
+	spec.Volumes = append(spec.Volumes,
+		v1.Volume{
+			Name:         "tls-certs",
+			VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: makeTLSSecretName(nodeSpecUniqueStr)}},
+		},
+		v1.Volume{
+			Name:         "tls-keystores",
+			VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+		},
+	)
+
+	script := fmt.Sprintf("openssl pkcs12 -export -in %[1]s/tls.crt -inkey %[1]s/tls.key -certfile %[1]s/ca.crt -name %[2]s -out %[3]s/keystore.p12 -passout env:%[4]s"+
+		" && openssl pkcs12 -export -nokeys -in %[1]s/ca.crt -jdktrust anyExtendedKeyUsage -out %[3]s/truststore.p12 -passout env:%[4]s",
+		tlsCertsMountPath, tlsCertAlias, tlsKeystoresMountPath, tlsKeystorePasswordEnv)
+	spec.InitContainers = append(spec.InitContainers, v1.Container{
+		Name:    "tls-keystores",
+		Image:   firstNonEmptyStr(m.Spec.TLS.InitImage, defaultTLSInitImage),
+		Command: []string{"sh", "-c", script},
+		Env:     []v1.EnvVar{passwordEnv},
+		VolumeMounts: []v1.VolumeMount{
+			{Name: "tls-certs", MountPath: tlsCertsMountPath, ReadOnly: true},
+			{Name: "tls-keystores", MountPath: tlsKeystoresMountPath},
+		},
+		SecurityContext: spec.Containers[0].SecurityContext,
+	})
+
+	druid := &spec.Containers[0]
+	druid.Env = append(druid.Env, passwordEnv)
+	druid.VolumeMounts = append(druid.VolumeMounts, v1.VolumeMount{Name: "tls-keystores", MountPath: tlsKeystoresMountPath, ReadOnly: true})
+	druid.LivenessProbe = updateSchemeInProbe(druid.LivenessProbe)
+	druid.ReadinessProbe = updateSchemeInProbe(druid.ReadinessProbe)
+	druid.StartupProbe = updateSchemeInProbe(druid.StartupProbe)
+}
+
+// updateSchemeInProbe returns a copy of the probe using HTTPS, the probe belongs to the CR spec.
+func updateSchemeInProbe(probe *v1.Probe) *v1.Probe {
+	if probe == nil || probe.HTTPGet == nil {
+		return probe
+	}
+	probe = probe.DeepCopy()
+	probe.HTTPGet.Scheme = v1.URISchemeHTTPS
+	return probe
+}
+
+// reconcileDruidTLS ensures the certificate of the node spec and the keystore password exist, and returns the hash
+// of the certificate. The hash is part of the pod template, so that the pods are restarted on rotation.
+// An empty hash is returned while the certificate is not issued yet.
+func reconcileDruidTLS(sdk client.Client, nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, nodeSpecUniqueStr string, ls map[string]string, certificateNames map[string]bool, emitEvents EventEmitter) (string, error) {
+	if m.Spec.TLS == nil {
+		return "", nil
+	}
+
+	if err := ensureTLSKeystorePassword(sdk, m, emitEvents); err != nil {
+		return "", err
+	}
+
+	dnsNames := getTLSDNSNames(nodeSpec, m, nodeSpecUniqueStr)
+	if len(dnsNames) == 0 {
+		return "", fmt.Errorf("node spec [%s] has no service to issue a TLS certificate for", nodeSpecUniqueStr)
+	}
+	if m.Spec.TLS.Provider == tlsProviderCertManager {
+		if _, err := sdkCreateOrUpdateAsNeeded(sdk,
+			func() (object, error) { return makeCertManagerCertificate(m, nodeSpecUniqueStr, ls, dnsNames), nil },
+			func() object { return makeCertManagerCertificateEmptyObj() },
+			certManagerCertificateIsEquals, noopUpdaterFn, m, certificateNames, emitEvents); err != nil {
+			return "", err
+		}
+	} else if err := ensureBuiltInTLSCertificate(sdk, m, nodeSpecUniqueStr, ls, dnsNames, emitEvents); err != nil {
+		return "", err
+	}
+
+	secret := &v1.Secret{}
+	if err := sdk.Get(context.TODO(), *namespacedName(makeTLSSecretName(nodeSpecUniqueStr), m.Namespace), secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return getJSONHash([]string{string(secret.Data["tls.crt"]), string(secret.Data[tlsCAKey])})
+}
+
+func makeCertManagerCertificate(m *v1alpha1.Druid, nodeSpecUniqueStr string, ls map[string]string, dnsNames []string) *unstructured.Unstructured {
+	issuerRef := m.Spec.TLS.IssuerRef
+	if issuerRef == nil {
+		issuerRef = &v1alpha1.DruidTLSIssuerRef{}
+	}
+	names := make([]interface{}, len(dnsNames))
+	for i, n := range dnsNames {
+		names[i] = n
+	}
+	labels := map[string]interface{}{}
+	for k, v := range ls {
+		labels[k] = v
+	}
+
+	cert := makeCertManagerCertificateEmptyObj()
+	cert.Object["metadata"] = map[string]interface{}{
+		"name":      makeTLSSecretName(nodeSpecUniqueStr),
+		"namespace": m.Namespace,
+		"labels":    labels,
+	}
+	cert.Object["spec"] = map[string]interface{}{
+		"secretName": makeTLSSecretName(nodeSpecUniqueStr),
+		"commonName": dnsNames[0],
+		"dnsNames":   names,
+		"duration":   getTLSDuration(m).String(),
+		"usages":     []interface{}{"server auth", "client auth"},
+		"issuerRef": map[string]interface{}{
+			"name":  issuerRef.Name,
+			"kind":  firstNonEmptyStr(issuerRef.Kind, "Issuer"),
+			"group": firstNonEmptyStr(issuerRef.Group, "cert-manager.io"),
+		},
+	}
+	return cert
+}
+
+func makeCertManagerCertificateEmptyObj() *unstructured.Unstructured {
+	cert := &unstructured.Unstructured{}
+	cert.SetAPIVersion("cert-manager.io/v1")
+	cert.SetKind("Certificate")
+	return cert
+}
+
+func makeCertManagerCertificateListEmptyObj() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion("cert-manager.io/v1")
+	list.SetKind("CertificateList")
+	return list
+}
+
+// certManagerCertificateIsEquals returns true if the specs of the Certificates are equal, to revert changes made out
+// of the operator.
+func certManagerCertificateIsEquals(prev, curr object) bool {
+	return reflect.DeepEqual(prev.(*unstructured.Unstructured).Object["spec"], curr.(*unstructured.Unstructured).Object["spec"])
+}
+
+// isCertManagerInstalled returns true if the cert-manager Certificate API is served, so that Certificates left by
+// removed node specs or a former provider can be listed.
+func isCertManagerInstalled(sdk client.Client) bool {
+	_, err := sdk.RESTMapper().RESTMapping(schema.GroupKind{Group: "cert-manager.io", Kind: "Certificate"}, "v1")
+	return err == nil
+}
+
+// ensureTLSKeystorePassword creates the Secret holding the password of the keystores, once.
+func ensureTLSKeystorePassword(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEmitter) error {
+	name := makeTLSKeystorePasswordSecretName(m)
+	if err := sdk.Get(context.TODO(), *namespacedName(name, m.Namespace), &v1.Secret{}); err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	password := make([]byte, 24)
+	if _, err := rand.Read(password); err != nil {
+		return err
+	}
+	secret := makeTLSSecret(m, name, makeLabelsForDruid(m.Name), map[string][]byte{
+		tlsKeystorePasswordKey: []byte(base64.RawURLEncoding.EncodeToString(password)),
+	})
+	_, err := writers.Create(context.TODO(), sdk, m, secret, emitEvents)
+	return err
+}
+
+// ensureBuiltInTLSCertificate issues the certificate of the node spec from the built-in CA, if missing, issued by
+// another CA, issued for other names or when a third of its validity remains.
+func ensureBuiltInTLSCertificate(sdk client.Client, m *v1alpha1.Druid, nodeSpecUniqueStr string, ls map[string]string, dnsNames []string, emitEvents EventEmitter) error {
+	ca, err := ensureBuiltInTLSCA(sdk, m, emitEvents)
+	if err != nil {
+		return err
+	}
+
+	prev := &v1.Secret{}
+	exists := true
+	if err := sdk.Get(context.TODO(), *namespacedName(makeTLSSecretName(nodeSpecUniqueStr), m.Namespace), prev); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		exists = false
+	}
+	if exists && !isTLSRenewalNeeded(prev.Data, ca.Data[tlsCAKey], dnsNames, getTLSDuration(m), time.Now()) {
+		return nil
+	}
+
+	certPEM, keyPEM, err := issueTLSCertificate(ca.Data[tlsCAKey], ca.Data[tlsCAPrivateKey], dnsNames, getTLSDuration(m))
+	if err != nil {
+		return err
+	}
+	secret := makeTLSSecret(m, makeTLSSecretName(nodeSpecUniqueStr), ls, map[string][]byte{
+		"tls.crt": certPEM,
+		"tls.key": keyPEM,
+		tlsCAKey:  ca.Data[tlsCAKey],
+	})
+
+	if !exists {
+		_, err = writers.Create(context.TODO(), sdk, m, secret, emitEvents)
+		return err
+	}
+	secret.SetResourceVersion(prev.GetResourceVersion())
+	_, err = writers.Update(context.TODO(), sdk, m, secret, emitEvents)
+	return err
+}
+
+// ensureBuiltInTLSCA returns the Secret of the built-in CA, generated once.
+func ensureBuiltInTLSCA(sdk client.Client, m *v1alpha1.Druid, emitEvents EventEmitter) (*v1.Secret, error) {
+	ca := &v1.Secret{}
+	err := sdk.Get(context.TODO(), *namespacedName(makeTLSCASecretName(m), m.Namespace), ca)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return ca, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template, err := makeTLSCertificateTemplate(fmt.Sprintf("%s-druid-ca", m.Name), nil, tlsCADuration)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	template.ExtKeyUsage = nil
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := encodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ca = makeTLSSecret(m, makeTLSCASecretName(m), makeLabelsForDruid(m.Name), map[string][]byte{
+		tlsCAKey:        pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		tlsCAPrivateKey: keyPEM,
+	})
+	if _, err := writers.Create(context.TODO(), sdk, m, ca, emitEvents); err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+// isTLSRenewalNeeded returns true if the certificate of the Secret data is not valid for the CA and names,
+// or a third of its validity remains.
+func isTLSRenewalNeeded(data map[string][]byte, caPEM []byte, dnsNames []string, duration time.Duration, now time.Time) bool {
+	if !bytes.Equal(data[tlsCAKey], caPEM) {
+		return true
+	}
+	block, _ := pem.Decode(data["tls.crt"])
+	if block == nil {
+		return true
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return true
+	}
+	if cert.NotAfter.Sub(now) < duration/3 {
+		return true
+	}
+	names := append([]string{}, cert.DNSNames...)
+	sort.Strings(names)
+	return !reflect.DeepEqual(names, dnsNames)
+}
+
+// issueTLSCertificate returns the PEM certificate and key for the names, signed by the CA.
+func issueTLSCertificate(caPEM, caKeyPEM []byte, dnsNames []string, duration time.Duration) ([]byte, []byte, error) {
+	caBlock, _ := pem.Decode(caPEM)
+	caKeyBlock, _ := pem.Decode(caKeyPEM)
+	if caBlock == nil || caKeyBlock == nil {
+		return nil, nil, errors.New("invalid built-in CA Secret")
+	}
+	caCert, err := x509.ParseCertificate(caBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	caKey, err := x509.ParsePKCS8PrivateKey(caKeyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template, err := makeTLSCertificateTemplate(dnsNames[0], dnsNames, duration)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := encodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+func makeTLSCertificateTemplate(commonName string, dnsNames []string, duration time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"druid-operator"}},
+		DNSNames:              dnsNames,
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.Add(duration),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}, nil
+}
+
+func encodePrivateKeyPEM(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func makeTLSSecret(m *v1alpha1.Druid, name string, ls map[string]string, data map[string][]byte) *v1.Secret {
+	secret := &v1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: m.Namespace,
+			Labels:    ls,
+		},
+		Data: data,
+	}
+	addOwnerRefToObject(secret, asOwner(m))
+	return secret
+}
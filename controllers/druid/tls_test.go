@@ -0,0 +1,107 @@
+package druid
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestIssueTLSCertificateAndRenewal(t *testing.T) {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template, _ := makeTLSCertificateTemplate("test-ca", nil, tlsCADuration)
+	template.IsCA = true
+	template.KeyUsage = x509.KeyUsageCertSign
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	caKeyPEM, _ := encodePrivateKeyPEM(caKey)
+
+	dnsNames := []string{"druid-brokers", "druid-brokers.ns.svc"}
+	certPEM, _, err := issueTLSCertificate(caPEM, caKeyPEM, dnsNames, 90*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string][]byte{"tls.crt": certPEM, tlsCAKey: caPEM}
+
+	now := time.Now()
+	if isTLSRenewalNeeded(data, caPEM, dnsNames, 90*24*time.Hour, now) {
+		t.Errorf("fresh certificate must not be renewed")
+	}
+	if !isTLSRenewalNeeded(data, caPEM, dnsNames, 90*24*time.Hour, now.Add(61*24*time.Hour)) {
+		t.Errorf("certificate with less than a third of its validity must be renewed")
+	}
+	if !isTLSRenewalNeeded(data, caPEM, []string{"druid-brokers"}, 90*24*time.Hour, now) {
+		t.Errorf("certificate issued for other names must be renewed")
+	}
+	if !isTLSRenewalNeeded(data, []byte("other"), dnsNames, 90*24*time.Hour, now) {
+		t.Errorf("certificate issued by another CA must be renewed")
+	}
+}
+
+func TestMakePodSpecWithTLS(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	clusterSpec.Spec.TLS = &v1alpha1.DruidTLSSpec{}
+	nodeSpec := clusterSpec.Spec.Nodes["brokers"]
+	nodeSpecUniqueStr := makeNodeSpecificUniqueString(clusterSpec, "brokers")
+
+	spec := makePodSpec(&nodeSpec, clusterSpec, nodeSpecUniqueStr, "sha")
+	if len(spec.InitContainers) != 1 || spec.InitContainers[0].Image != defaultTLSInitImage {
+		t.Errorf("expected the keystores init container, got %v", spec.InitContainers)
+	}
+	if probe := spec.Containers[0].ReadinessProbe; probe != nil && probe.HTTPGet != nil {
+		if probe.HTTPGet.Scheme != v1.URISchemeHTTPS {
+			t.Errorf("expected HTTPS readiness probe")
+		}
+		if nodeSpec.ReadinessProbe != nil && nodeSpec.ReadinessProbe.HTTPGet.Scheme == v1.URISchemeHTTPS {
+			t.Errorf("probe of the CR spec must not be modified")
+		}
+	}
+}
+
+func TestMakeTLSRuntimeProperties(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	clusterSpec.Spec.TLS = &v1alpha1.DruidTLSSpec{}
+	nodeSpec := clusterSpec.Spec.Nodes["brokers"]
+	nodeSpec.Kind = "Deployment"
+	clusterSpec.Spec.Nodes["brokers"] = nodeSpec
+
+	// pods of Deployments advertise names not covered by the certificates
+	if props := makeTLSRuntimeProperties(&nodeSpec, clusterSpec); !strings.Contains(props, "druid.client.https.validateHostnames=false") {
+		t.Errorf("hostnames must not be validated by default, got [%s]", props)
+	}
+	if _, err := verifyDruidSpec(clusterSpec); err != nil && strings.Contains(err.Error(), "validateHostnames") {
+		t.Errorf("Deployment node spec must be accepted without hostname validation, got [%v]", err)
+	}
+
+	clusterSpec.Spec.TLS.ValidateHostnames = true
+	if props := makeTLSRuntimeProperties(&nodeSpec, clusterSpec); !strings.Contains(props, "druid.client.https.validateHostnames=true") {
+		t.Errorf("hostname validation must be enabled, got [%s]", props)
+	}
+	if _, err := verifyDruidSpec(clusterSpec); err == nil || !strings.Contains(err.Error(), "Node[brokers] tls validateHostnames requires kind StatefulSet") {
+		t.Errorf("hostname validation must be rejected with a Deployment node spec, got [%v]", err)
+	}
+}
+
+func TestCertManagerCertificateIsEquals(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	clusterSpec.Spec.TLS = &v1alpha1.DruidTLSSpec{Provider: tlsProviderCertManager, IssuerRef: &v1alpha1.DruidTLSIssuerRef{Name: "issuer"}}
+	prev := makeCertManagerCertificate(clusterSpec, "broker", nil, []string{"broker", "broker.ns.svc"})
+	curr := makeCertManagerCertificate(clusterSpec, "broker", nil, []string{"broker", "broker.ns.svc"})
+	if !certManagerCertificateIsEquals(prev, curr) {
+		t.Errorf("Certificates of the same spec must be equal")
+	}
+	curr = makeCertManagerCertificate(clusterSpec, "broker", nil, []string{"broker"})
+	if certManagerCertificateIsEquals(prev, curr) {
+		t.Errorf("Certificates of different names must not be equal")
+	}
+}
@@ -1,10 +1,17 @@
 package druid
 
 import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
 	"os"
 	"reflect"
 	"strconv"
 	"strings"
+
+	v1 "k8s.io/api/core/v1"
 )
 
 func firstNonEmptyStr(s1 string, s2 string) string {
@@ -77,3 +84,91 @@ func Str2Int(s string) int {
 	}
 	return i
 }
+
+// getPropertyValue returns the value of the key in java properties content, last definition wins.
+func getPropertyValue(properties, key string) (string, bool) {
+	value, found := "", false
+	for _, line := range strings.Split(properties, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			continue
+		}
+		if strings.TrimSpace(line[:i]) == key {
+			value, found = strings.TrimSpace(line[i+1:]), true
+		}
+	}
+	return value, found
+}
+
+// isJSONSubset returns true if every field of desired is present with the same value in actual.
+// Both values are expected to be unmarshalled json, arrays must have the same length and are compared in order.
+// Used to compare the state declared in CRs with the state returned by druid APIs, which adds defaulted fields.
+func isJSONSubset(desired, actual interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range d {
+			if !isJSONSubset(v, a[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !isJSONSubset(d[i], a[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(desired, actual)
+	}
+}
+
+// toJSONValue converts v to its unmarshalled json representation, to be compared with isJSONSubset.
+func toJSONValue(v interface{}) (interface{}, error) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(bytes, &out)
+	return out, err
+}
+
+// getJSONHash returns the hash of the json representation of v, map keys are sorted by json marshalling.
+func getJSONHash(v interface{}) (string, error) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sha1Bytes := sha1.Sum(bytes)
+	return base64.StdEncoding.EncodeToString(sha1Bytes[:]), nil
+}
+
+// findPodByHost returns the pod of the druid node advertised with host and port, druid nodes advertise the IP
+// or the hostname of their pod.
+func findPodByHost(hostAndPort string, pods map[string]*v1.Pod) *v1.Pod {
+	host, _, err := net.SplitHostPort(hostAndPort)
+	if err != nil {
+		host = hostAndPort
+	}
+	for _, pod := range pods {
+		if (pod.Status.PodIP != "" && host == pod.Status.PodIP) || host == pod.Name ||
+			(pod.Spec.Subdomain != "" && host == fmt.Sprintf("%s.%s.%s.svc", pod.Name, pod.Spec.Subdomain, pod.Namespace)) {
+			return pod
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestFirstNonNilValue(t *testing.T) {
@@ -70,3 +71,65 @@ func linearsearch(list []string, key string) bool {
 	}
 	return false
 }
+
+func TestGetPropertyValue(t *testing.T) {
+	props := "druid.service=druid/historical\n# druid.server.tier=commented\ndruid.server.tier = hot\ndruid.server.maxSize: 10\n"
+
+	if v, ok := getPropertyValue(props, "druid.server.tier"); !ok || v != "hot" {
+		t.Errorf("unexpected tier [%s]", v)
+	}
+	if v, ok := getPropertyValue(props, "druid.server.maxSize"); !ok || v != "10" {
+		t.Errorf("unexpected maxSize [%s]", v)
+	}
+	if _, ok := getPropertyValue(props, "druid.missing"); ok {
+		t.Fail()
+	}
+}
+
+func TestIsJSONSubset(t *testing.T) {
+	var actual interface{}
+	json.Unmarshal([]byte(`[{"type":"loadByPeriod","period":"P1M","includeFuture":true,"tieredReplicants":{"hot":2}}]`), &actual)
+
+	var desired interface{}
+	json.Unmarshal([]byte(`[{"type":"loadByPeriod","period":"P1M","tieredReplicants":{"hot":2}}]`), &desired)
+	if !isJSONSubset(desired, actual) {
+		t.Errorf("defaulted fields must be ignored")
+	}
+
+	json.Unmarshal([]byte(`[{"type":"loadByPeriod","period":"P1M","tieredReplicants":{"hot":1}}]`), &desired)
+	if isJSONSubset(desired, actual) {
+		t.Errorf("changed value must be detected")
+	}
+
+	json.Unmarshal([]byte(`[]`), &desired)
+	if isJSONSubset(desired, actual) {
+		t.Errorf("removed items must be detected")
+	}
+}
+
+func TestFindPodByHost(t *testing.T) {
+	pods := map[string]*v1.Pod{
+		"druid-mm-0": {
+			ObjectMeta: metav1.ObjectMeta{Name: "druid-mm-0", Namespace: "ns"},
+			Spec:       v1.PodSpec{Subdomain: "druid-mm"},
+			Status:     v1.PodStatus{PodIP: "10.0.0.1"},
+		},
+		"druid-mm-1": {
+			ObjectMeta: metav1.ObjectMeta{Name: "druid-mm-1", Namespace: "ns"},
+			Spec:       v1.PodSpec{Subdomain: "druid-mm"},
+			Status:     v1.PodStatus{PodIP: "10.0.0.2"},
+		},
+	}
+
+	for host, expected := range map[string]string{
+		"10.0.0.2:8091":                   "druid-mm-1",
+		"druid-mm-0:8091":                 "druid-mm-0",
+		"druid-mm-1.druid-mm.ns.svc:8091": "druid-mm-1",
+		"10.0.0.3:8091":                   "",
+	} {
+		pod := findPodByHost(host, pods)
+		if (pod == nil && expected != "") || (pod != nil && pod.Name != expected) {
+			t.Errorf("unexpected pod [%v] for worker [%s]", pod, host)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package druid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultAutoExpandThresholdPercent = 80
+	serverMaxSizeProperty             = "druid.server.maxSize"
+	segmentCacheLocationsProperty     = "druid.segmentCache.locations"
+)
+
+var (
+	druidBytesRegex = regexp.MustCompile(`^([0-9]+)\s*([a-zA-Z]*)$`)
+	// units of druid human readable bytes, decimal and binary
+	druidBytesUnits = map[string]int64{
+		"": 1, "k": 1000, "m": 1000 * 1000, "g": 1000 * 1000 * 1000, "t": 1000 * 1000 * 1000 * 1000, "p": 1000 * 1000 * 1000 * 1000 * 1000,
+		"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40, "pib": 1 << 50,
+	}
+)
+
+func isAutoExpanded(nodeSpec *v1alpha1.DruidNodeSpec) bool {
+	return nodeSpec.AutoExpand != nil && nodeSpec.NodeType == historical && nodeSpec.Kind != "Deployment"
+}
+
+// isVolumeAutoExpanded returns true if the operator has grown the segment cache volume of the node spec.
+func isVolumeAutoExpanded(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, key string) bool {
+	if !isAutoExpanded(nodeSpec) {
+		return false
+	}
+	_, found := m.Status.AutoExpand[key]
+	return found
+}
+
+// isVolumeClaimTemplateExpandable returns true if the storage class of the volume claim template allows volume
+// expansion. Volume claim templates without storage class are not expanded, the default class being unknown.
+func isVolumeClaimTemplateExpandable(sdk client.Client, m *v1alpha1.Druid, vct *v1.PersistentVolumeClaim, emitEvents EventEmitter) (bool, error) {
+	if vct.Spec.StorageClassName == nil || *vct.Spec.StorageClassName == "" {
+		return false, nil
+	}
+	sc, err := readers.Get(context.TODO(), sdk, *vct.Spec.StorageClassName, m, func() object { return makeStorageClassEmptyObj() }, emitEvents)
+	if err != nil {
+		return false, err
+	}
+	allow := sc.(*storage.StorageClass).AllowVolumeExpansion
+	return allow != nil && *allow, nil
+}
+
+// getAutoExpandVolumeClaimTemplateIndex returns the index of the volume claim template holding the segment cache, -1 if not found.
+func getAutoExpandVolumeClaimTemplateIndex(nodeSpec *v1alpha1.DruidNodeSpec) int {
+	for i, vct := range nodeSpec.VolumeClaimTemplates {
+		if nodeSpec.AutoExpand.VolumeClaimTemplate == "" || vct.Name == nodeSpec.AutoExpand.VolumeClaimTemplate {
+			return i
+		}
+	}
+	return -1
+}
+
+// getVolumeMountPath returns the path where the volume is mounted in the druid container, empty if not mounted.
+func getVolumeMountPath(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, volumeName string) string {
+	for _, vm := range getVolumeMounts(nodeSpec, m) {
+		if vm.Name == volumeName {
+			return vm.MountPath
+		}
+	}
+	return ""
+}
+
+// applyVolumeAutoExpand sets the size grown by the operator on the segment cache volume claim template of the node spec,
+// and scales the segment cache properties in proportion. Volume claim templates are copied, the CR spec is left as is.
+func applyVolumeAutoExpand(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, key string) error {
+	status, found := m.Status.AutoExpand[key]
+	i := getAutoExpandVolumeClaimTemplateIndex(nodeSpec)
+	if !found || i < 0 {
+		return nil
+	}
+	specSize := nodeSpec.VolumeClaimTemplates[i].Spec.Resources.Requests[v1.ResourceStorage]
+	if status.Size.Cmp(specSize) <= 0 {
+		return nil
+	}
+
+	vcts := make([]v1.PersistentVolumeClaim, len(nodeSpec.VolumeClaimTemplates))
+	for j := range nodeSpec.VolumeClaimTemplates {
+		vcts[j] = *nodeSpec.VolumeClaimTemplates[j].DeepCopy()
+	}
+	vcts[i].Spec.Resources.Requests[v1.ResourceStorage] = status.Size
+	nodeSpec.VolumeClaimTemplates = vcts
+
+	properties, err := scaleSegmentCacheProperties(nodeSpec.RuntimeProperties, getVolumeMountPath(nodeSpec, m, vcts[i].Name), specSize.Value(), status.Size.Value())
+	if err != nil {
+		return err
+	}
+	nodeSpec.RuntimeProperties = properties
+	return nil
+}
+
+// scaleSegmentCacheProperties returns the properties with druid.server.maxSize, and the maxSize of the segment cache
+// locations under mountPath, scaled by to/from. Scaled values are appended to take precedence.
+func scaleSegmentCacheProperties(properties, mountPath string, from, to int64) (string, error) {
+	scale := func(size int64) int64 {
+		scaled := new(big.Int).Mul(big.NewInt(size), big.NewInt(to))
+		return scaled.Div(scaled, big.NewInt(from)).Int64()
+	}
+	overrides := ""
+
+	if value, found := getPropertyValue(properties, serverMaxSizeProperty); found {
+		size, err := parseDruidBytes(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s due to [%s]", serverMaxSizeProperty, err.Error())
+		}
+		overrides = fmt.Sprintf("%s%s=%d\n", overrides, serverMaxSizeProperty, scale(size))
+	}
+
+	if value, found := getPropertyValue(properties, segmentCacheLocationsProperty); found {
+		locations := []map[string]interface{}{}
+		if err := json.Unmarshal([]byte(value), &locations); err != nil {
+			return "", fmt.Errorf("invalid %s due to [%s]", segmentCacheLocationsProperty, err.Error())
+		}
+		for _, location := range locations {
+			path, _ := location["path"].(string)
+			maxSize, ok := location["maxSize"]
+			if !ok || (mountPath != "" && !isPathUnder(path, mountPath)) {
+				continue
+			}
+			size, err := parseDruidBytes(maxSize)
+			if err != nil {
+				return "", fmt.Errorf("invalid maxSize of segment cache location [%s] due to [%s]", path, err.Error())
+			}
+			location["maxSize"] = scale(size)
+		}
+		bytes, err := json.Marshal(locations)
+		if err != nil {
+			return "", err
+		}
+		overrides = fmt.Sprintf("%s%s=%s\n", overrides, segmentCacheLocationsProperty, string(bytes))
+	}
+
+	if overrides == "" {
+		return properties, nil
+	}
+	return fmt.Sprintf("%s\n%s", properties, overrides), nil
+}
+
+// parseDruidBytes parses a size in bytes, as a json number or a druid human readable string such as 10g or 10GiB.
+func parseDruidBytes(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case float64:
+		return int64(v), nil
+	case string:
+		match := druidBytesRegex.FindStringSubmatch(strings.TrimSpace(v))
+		if match == nil {
+			return 0, fmt.Errorf("invalid size [%s]", v)
+		}
+		unit, ok := druidBytesUnits[strings.ToLower(match[2])]
+		if !ok {
+			return 0, fmt.Errorf("invalid unit of size [%s]", v)
+		}
+		size, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return size * unit, nil
+	default:
+		return 0, fmt.Errorf("invalid size [%v]", value)
+	}
+}
+
+// reconcileVolumeAutoExpand grows the segment cache volume of the node spec by a step when a server of the node spec
+// uses more than the threshold of its maxSize. Volume is not grown again until the pvcs have been resized and the
+// cluster is ready, so that servers report the scaled maxSize.
+func reconcileVolumeAutoExpand(sdk client.Client, nodeSpec *v1alpha1.DruidNodeSpec, key, nodeSpecUniqueStr string, ls map[string]string, m *v1alpha1.Druid, emitEvents EventEmitter) error {
+	i := getAutoExpandVolumeClaimTemplateIndex(nodeSpec)
+	if i < 0 || m.Status.DruidNodeStatus.DruidNodeConditionType != v1alpha1.DruidClusterReady {
+		return nil
+	}
+	vctName := nodeSpec.VolumeClaimTemplates[i].Name
+	current := getAutoExpandSize(nodeSpec, m, key)
+	if current.Cmp(nodeSpec.AutoExpand.MaxSize) >= 0 {
+		return nil
+	}
+
+	// pvcs created from volume claim templates are named <template>-<pod>
+	pvcList, err := listPersistentVolumeClaims(sdk, m, makeLabelsForPVCOfNodeSpec(m, nodeSpecUniqueStr), emitEvents)
+	if err != nil {
+		return err
+	}
+	for _, obj := range pvcList {
+		pvc := obj.(*v1.PersistentVolumeClaim)
+		capacity := pvc.Status.Capacity[v1.ResourceStorage]
+		if strings.HasPrefix(pvc.Name, vctName+"-") && capacity.Cmp(current) < 0 {
+			return nil
+		}
+	}
+
+	podList, err := readers.List(context.TODO(), sdk, m, ls, emitEvents, func() objectList { return makePodList() }, func(listObj runtime.Object) []object {
+		items := listObj.(*v1.PodList).Items
+		result := make([]object, len(items))
+		for i := 0; i < len(items); i++ {
+			result[i] = &items[i]
+		}
+		return result
+	})
+	if err != nil {
+		return err
+	}
+	pods := map[string]*v1.Pod{}
+	for _, obj := range podList {
+		pods[obj.GetName()] = obj.(*v1.Pod)
+	}
+
+	c, err := newDruidAPIClient(sdk, m, coordinator)
+	if err != nil {
+		return err
+	}
+	var servers []struct {
+		Host     string `json:"host"`
+		CurrSize int64  `json:"currSize"`
+		MaxSize  int64  `json:"maxSize"`
+	}
+	if _, err := c.do(http.MethodGet, "/druid/coordinator/v1/servers?simple", nil, &servers); err != nil {
+		emitEvents.EmitEventGeneric(m, "DruidVolumeAutoExpandPollFail", "", fmt.Errorf("failed to get servers for node [%s] due to [%s]", key, err.Error()))
+		return nil
+	}
+
+	threshold := int64(defaultAutoExpandThresholdPercent)
+	if nodeSpec.AutoExpand.ThresholdPercent != nil {
+		threshold = int64(*nodeSpec.AutoExpand.ThresholdPercent)
+	}
+	full := ""
+	var usedPercent int64
+	for _, s := range servers {
+		pod := findPodByHost(s.Host, pods)
+		if pod == nil || s.MaxSize <= 0 {
+			continue
+		}
+		if s.CurrSize*100 >= threshold*s.MaxSize {
+			full, usedPercent = pod.Name, s.CurrSize*100/s.MaxSize
+			break
+		}
+	}
+	if full == "" {
+		return nil
+	}
+
+	// volume claim template of a storage class without expansion can't be changed on the StatefulSet
+	if expandable, err := isVolumeClaimTemplateExpandable(sdk, m, &nodeSpec.VolumeClaimTemplates[i], emitEvents); err != nil {
+		return err
+	} else if !expandable {
+		emitEvents.EmitEventGeneric(m, "DruidVolumeAutoExpandFail", "",
+			fmt.Errorf("volume [%s] of node [%s] is not expanded, its storage class does not allow volume expansion", vctName, key))
+		return nil
+	}
+
+	size := current.DeepCopy()
+	size.Add(nodeSpec.AutoExpand.Step)
+	if size.Cmp(nodeSpec.AutoExpand.MaxSize) > 0 {
+		size = nodeSpec.AutoExpand.MaxSize.DeepCopy()
+	}
+
+	autoExpand := make(map[string]v1alpha1.VolumeAutoExpandStatus, len(m.Status.AutoExpand)+1)
+	for k, v := range m.Status.AutoExpand {
+		autoExpand[k] = v
+	}
+	now := metav1.Now()
+	autoExpand[key] = v1alpha1.VolumeAutoExpandStatus{Size: size, ExpandedAt: &now}
+	if err := druidStatusFieldsPatcher(sdk, map[string]interface{}{"autoExpand": autoExpand}, m, emitEvents); err != nil {
+		return err
+	}
+	m.Status.AutoExpand = autoExpand
+
+	msg := fmt.Sprintf("Expanding volume [%s] of node [%s] from [%s] to [%s], server [%s] uses %d%% of its maxSize",
+		vctName, key, current.String(), size.String(), full, usedPercent)
+	logger.Info(msg, "name", m.Name, "namespace", m.Namespace)
+	emitEvents.EmitEventGeneric(m, "DruidVolumeAutoExpand", msg, nil)
+	return nil
+}
+
+// getAutoExpandSize returns the size of the segment cache volume of the node spec, including the growth by the operator.
+func getAutoExpandSize(nodeSpec *v1alpha1.DruidNodeSpec, m *v1alpha1.Druid, key string) resource.Quantity {
+	i := getAutoExpandVolumeClaimTemplateIndex(nodeSpec)
+	if i < 0 {
+		return resource.Quantity{}
+	}
+	size := nodeSpec.VolumeClaimTemplates[i].Spec.Resources.Requests[v1.ResourceStorage]
+	if status, found := m.Status.AutoExpand[key]; found && status.Size.Cmp(size) > 0 {
+		return status.Size
+	}
+	return size
+}
@@ -0,0 +1,109 @@
+package druid
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/druid-io/druid-operator/apis/druid/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseDruidBytes(t *testing.T) {
+	for value, expected := range map[interface{}]int64{
+		"10737418240":   10737418240,
+		"10g":           10000000000,
+		"10GiB":         10737418240,
+		"5 m":           5000000,
+		float64(1024.0): 1024,
+	} {
+		if size, err := parseDruidBytes(value); err != nil || size != expected {
+			t.Errorf("expected [%d] for [%v], got [%d] [%v]", expected, value, size, err)
+		}
+	}
+	if _, err := parseDruidBytes("10x"); err == nil {
+		t.Errorf("invalid unit must be rejected")
+	}
+}
+
+func TestApplyVolumeAutoExpand(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	nodeSpec := clusterSpec.Spec.Nodes["historicals"]
+	nodeSpec.RuntimeProperties = `druid.service=druid/historical
+druid.segmentCache.locations=[{"path":"/druid/data/segments","maxSize":"1g"},{"path":"/tmp/segments","maxSize":1000}]
+druid.server.maxSize=1000000000`
+	nodeSpec.AutoExpand = &v1alpha1.VolumeAutoExpandSpec{Step: resource.MustParse("2Gi"), MaxSize: resource.MustParse("10Gi")}
+	clusterSpec.Status.AutoExpand = map[string]v1alpha1.VolumeAutoExpandStatus{
+		"historicals": {Size: resource.MustParse("4Gi")},
+	}
+
+	expanded := nodeSpec
+	if err := applyVolumeAutoExpand(&expanded, clusterSpec, "historicals"); err != nil {
+		t.Fatal(err)
+	}
+
+	size := expanded.VolumeClaimTemplates[0].Spec.Resources.Requests[v1.ResourceStorage]
+	if size.String() != "4Gi" {
+		t.Errorf("expected expanded size [4Gi], got [%s]", size.String())
+	}
+	specSize := nodeSpec.VolumeClaimTemplates[0].Spec.Resources.Requests[v1.ResourceStorage]
+	if specSize.String() != "2Gi" {
+		t.Errorf("volume claim templates of the spec must not be modified, got [%s]", specSize.String())
+	}
+
+	for _, expected := range []string{
+		"druid.server.maxSize=2000000000",
+		`druid.segmentCache.locations=[{"maxSize":2000000000,"path":"/druid/data/segments"},{"maxSize":1000,"path":"/tmp/segments"}]`,
+	} {
+		if !strings.Contains(expanded.RuntimeProperties, expected) {
+			t.Errorf("expected [%s] in properties [%s]", expected, expanded.RuntimeProperties)
+		}
+	}
+	if value, _ := getPropertyValue(expanded.RuntimeProperties, serverMaxSizeProperty); value != "2000000000" {
+		t.Errorf("scaled maxSize must take precedence, got [%s]", value)
+	}
+}
+
+func TestIsVolumeAutoExpanded(t *testing.T) {
+	clusterSpec := readSampleDruidClusterSpec(t)
+	historicals := clusterSpec.Spec.Nodes["historicals"]
+	historicals.AutoExpand = &v1alpha1.VolumeAutoExpandSpec{Step: resource.MustParse("1Gi"), MaxSize: resource.MustParse("10Gi")}
+
+	if isVolumeAutoExpanded(&historicals, clusterSpec, "historicals") {
+		t.Errorf("volume must not be expanded before the operator grows it")
+	}
+	// status patches don't bump the generation of the CR
+	clusterSpec.Generation = 1
+	clusterSpec.Status.AutoExpand = map[string]v1alpha1.VolumeAutoExpandStatus{"historicals": {Size: resource.MustParse("3Gi")}}
+	if !isVolumeAutoExpanded(&historicals, clusterSpec, "historicals") {
+		t.Errorf("volume grown by the operator must be resized")
+	}
+
+	// the default storage class is unknown
+	if expandable, err := isVolumeClaimTemplateExpandable(nil, clusterSpec, &v1.PersistentVolumeClaim{}, nil); err != nil || expandable {
+		t.Errorf("volume claim template without storage class must not be expanded")
+	}
+}
+
+func TestGetVolumeClaimTemplatePVCs(t *testing.T) {
+	var pvcList []object
+	for _, name := range []string{
+		"data-volume-druid-cluster-historicals-0",
+		"data-volume-druid-cluster-historicals-1",
+		"data-volume-druid-cluster-historicals-cold-0",
+		"tmp-volume-druid-cluster-historicals-0",
+	} {
+		pvcList = append(pvcList, &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+
+	var names []string
+	for _, pvc := range getVolumeClaimTemplatePVCs(pvcList, "data-volume", "druid-cluster-historicals") {
+		names = append(names, pvc.GetName())
+	}
+	expected := []string{"data-volume-druid-cluster-historicals-0", "data-volume-druid-cluster-historicals-1"}
+	if !reflect.DeepEqual(expected, names) {
+		t.Errorf("expected pvcs %v of the volume claim template and sts only, got %v", expected, names)
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"os"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -54,6 +56,9 @@ func main() {
 		LeaderElectionID:       "e6946145.apache.org",
 		Namespace:              os.Getenv("WATCH_NAMESPACE"),
 		NewCache:               watchNamespaceCache(),
+		// Secrets are read from the API server, so that the content of all the Secrets of the watched namespaces is
+		// not cached by the operator
+		ClientDisableCacheFor: []client.Object{&corev1.Secret{}},
 	})
 
 	if err != nil {
@@ -66,6 +71,41 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (druid.NewDruidOperationReconciler(mgr)).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DruidOperation")
+		os.Exit(1)
+	}
+
+	if err = (druid.NewDruidRuleReconciler(mgr)).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DruidRule")
+		os.Exit(1)
+	}
+
+	if err = (druid.NewDruidSupervisorReconciler(mgr)).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DruidSupervisor")
+		os.Exit(1)
+	}
+
+	if err = (druid.NewDruidCompactionReconciler(mgr)).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DruidCompaction")
+		os.Exit(1)
+	}
+
+	if err = (druid.NewDruidLookupReconciler(mgr)).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DruidLookup")
+		os.Exit(1)
+	}
+
+	if err = (druid.NewDruidUserReconciler(mgr)).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DruidUser")
+		os.Exit(1)
+	}
+
+	if err = (druid.NewDruidRoleReconciler(mgr)).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DruidRole")
+		os.Exit(1)
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {